@@ -0,0 +1,139 @@
+package horcrux
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/codahale/sss"
+	"golang.org/x/text/unicode/norm"
+)
+
+// AddFragment adds a fragment for a new question to an existing fragment set,
+// e.g. for a new trustee, without changing any of the existing fragments. K
+// answers are needed to compute the new share, which lies on the same
+// polynomial as the others. frags must include every existing fragment,
+// including any extra fragments, so that the new one is given an unused ID;
+// they need no answers. The new fragment uses the same KDF parameters,
+// normalizer, locale, and answer separator as the first answer's fragment,
+// unless overridden with the given options, and is private if its question is.
+func AddFragment(frags []Fragment, answers []Answer, question, answer string, opts ...SplitOption) (Fragment, error) {
+	if len(answers) == 0 {
+		return Fragment{}, errors.New("horcrux: need at least 1 answer")
+	}
+
+	k := answers[0].K
+	if have := answersWeight(answers); have < k {
+		return Fragment{}, ErrTooFewAnswers{Need: k, Have: have}
+	}
+
+	all := append([]Answer(nil), answers...)
+	for _, f := range frags {
+		all = append(all, Answer{Fragment: f})
+	}
+
+	if err := checkSets(all); err != nil {
+		return Fragment{}, err
+	}
+
+	if err := checkUngrouped(all); err != nil {
+		return Fragment{}, err
+	}
+
+	question = norm.NFC.String(question)
+	hashed := hex.EncodeToString(hashQuestion(question))
+	maxID := 0
+	for _, a := range all {
+		if a.K != k {
+			return Fragment{}, fmt.Errorf("horcrux: fragment %d needs %d answers but fragment %d needs %d",
+				a.ID, a.K, answers[0].ID, k)
+		}
+
+		if a.Question == question || (a.QuestionIsHashed && a.Question == hashed) {
+			return Fragment{}, fmt.Errorf("horcrux: duplicate question %q", question)
+		}
+
+		if last := int(a.ID) + a.weight() - 1; last > maxID {
+			maxID = last
+		}
+	}
+
+	cfg, err := fragmentSplitConfig(answers[0].Fragment)
+	if err != nil {
+		return Fragment{}, err
+	}
+	cfg.hints = nil
+	cfg.weights = nil
+	cfg.privateQuestions = answers[0].QuestionIsHashed
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	w := cfg.weight(question)
+	if maxID+w > 255 {
+		return Fragment{}, errors.New("horcrux: no unused fragment IDs")
+	}
+	id := byte(maxID + 1)
+
+	if err := cfg.validate(); err != nil {
+		return Fragment{}, err
+	}
+
+	normalize, err := cfg.normalizer()
+	if err != nil {
+		return Fragment{}, err
+	}
+
+	if err := checkAnswerLengths(map[string]string{question: answer}, normalize,
+		cfg.minAnswerLength); err != nil {
+		return Fragment{}, err
+	}
+
+	normalized := answer
+	if normalize != nil {
+		normalized = normalize(answer)
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	shares, err := openShares(ctx, answers, &cfg)
+	defer func() {
+		for _, s := range shares {
+			zero(s)
+		}
+	}()
+	if err != nil {
+		return Fragment{}, err
+	}
+
+	// a weighted fragment holds the shares at consecutive IDs
+	var value []byte
+	for i := 0; i < w; i++ {
+		v := shareAt(shares, id+byte(i))
+		value = append(value, v...)
+		zero(v)
+	}
+	defer zero(value)
+
+	var f Fragment
+	err = encryptShare(ctx, &f, id, k, question, normalized, answer, value, &cfg)
+	if err != nil {
+		return Fragment{}, err
+	}
+	return f, nil
+}
+
+// shareAt returns the share with the given ID, which must not be one of the
+// given shares' IDs, on the polynomial through the given shares. sss.Combine
+// only interpolates at zero, but in GF(2^8), substituting t+x for t doesn't
+// change a polynomial's degree, and addition is XOR, so interpolating the
+// shares with their IDs XORed with x at zero interpolates the original
+// polynomial at x.
+func shareAt(shares map[byte][]byte, x byte) []byte {
+	shifted := make(map[byte][]byte, len(shares))
+	for id, v := range shares {
+		shifted[id^x] = v
+	}
+	return sss.Combine(shifted)
+}
@@ -0,0 +1,83 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAddFragment(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := allAnswers(frags)
+	f, err := AddFragment(frags, answers[:3], "What's your favorite color?", "Blue")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.ID != byte(len(frags)+1) || f.K != 3 {
+		t.Fatalf("Expected %d/%d but was %d/%d", len(frags)+1, 3, f.ID, f.K)
+	}
+
+	if !bytes.Equal(f.SetID, frags[0].SetID) {
+		t.Fatalf("Expected %x but was %x", frags[0].SetID, f.SetID)
+	}
+
+	// the new fragment combines with any of the old ones
+	for _, others := range [][]Answer{answers[:2], answers[2:]} {
+		s, err := Recover(append([]Answer{f.WithAnswer("blue")}, others...))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(s, secret) {
+			t.Fatalf("Expected %v but was %v", secret, s)
+		}
+	}
+}
+
+func TestAddFragmentTooFewAnswers(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AddFragment(frags, allAnswers(frags)[:2], "What's your favorite color?", "Blue")
+
+	expected := ErrTooFewAnswers{Need: 3, Have: 2}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestAddFragmentDuplicateQuestion(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = AddFragment(frags, allAnswers(frags)[:2], frags[3].Question, "Blue")
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestShareAt(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := DecryptShares(allAnswers(frags))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// any two shares determine the others
+	actual := shareAt(map[byte][]byte{1: shares[1], 2: shares[2]}, 4)
+	if !bytes.Equal(actual, shares[4]) {
+		t.Fatalf("Expected %v but was %v", shares[4], actual)
+	}
+}
@@ -0,0 +1,66 @@
+package horcrux
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/codahale/chacha20poly1305"
+)
+
+// An AEADFunc returns an AEAD which encrypts with the given 256-bit key. See
+// WithSplitAEAD.
+type AEADFunc func(key []byte) (cipher.AEAD, error)
+
+// newAEAD returns the AEAD which encrypts shares with the given key, and the
+// name of its cipher.
+func (c *splitConfig) newAEAD(key []byte) (cipher.AEAD, string, error) {
+	if c.aead == nil {
+		aead, err := chacha20poly1305.New(key)
+		return aead, CipherChaCha20Poly1305, err
+	}
+
+	if c.aeadName == "" {
+		return nil, "", errors.New("horcrux: custom AEAD has no name")
+	}
+
+	aead, err := c.aead(key)
+	return aead, c.aeadName, err
+}
+
+// checkCipher returns an error if the fragment's cipher is neither
+// ChaCha20Poly1305 nor one given to WithAEAD.
+func (c *recoverConfig) checkCipher(f Fragment) error {
+	if f.Cipher == "" || f.Cipher == CipherChaCha20Poly1305 {
+		return nil
+	}
+
+	if _, ok := c.aeads[f.Cipher]; !ok {
+		return fmt.Errorf("horcrux: fragment %d uses unsupported cipher %q",
+			f.ID, f.Cipher)
+	}
+	return nil
+}
+
+// open decrypts the fragment's share with the given key using its cipher.
+// ChaCha20Poly1305 shares are decrypted by the decryption backend.
+func (c *recoverConfig) open(ctx context.Context, f Fragment, key, ad []byte) ([]byte, error) {
+	if fn, ok := c.aeads[f.Cipher]; ok {
+		aead, err := fn(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(f.Nonce) != aead.NonceSize() {
+			return nil, fmt.Errorf("horcrux: fragment %d has a bad nonce", f.ID)
+		}
+		return aead.Open(nil, f.Nonce, f.Value, ad)
+	}
+
+	backend := c.backend
+	if backend == nil {
+		backend = DefaultDecryptionBackend
+	}
+	return backend.AEADDecrypt(ctx, f.Value, f.Nonce, key, ad)
+}
@@ -0,0 +1,84 @@
+package horcrux
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"testing"
+)
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func TestSplitRecoverCustomAEAD(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithSplitAEAD("AES-256-GCM", newGCM))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.Cipher != "AES-256-GCM" || len(f.Nonce) != GCMNonceSize {
+			t.Fatalf("Expected an AES-256-GCM fragment but was %v", f)
+		}
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	actual, err := Recover(answers, WithAEAD("AES-256-GCM", newGCM))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestRecoverCustomAEADWithoutOption(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithSplitAEAD("AES-256-GCM", newGCM))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	_, err = Recover(answers)
+
+	expected := `horcrux: fragment 1 uses unsupported cipher "AES-256-GCM"`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRecoverCustomAEADWrongAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithSplitAEAD("AES-256-GCM", newGCM))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer("wrong"),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	_, err = Recover(answers, WithAEAD("AES-256-GCM", newGCM))
+	var auth ErrAuthenticationFailed
+	if !errors.As(err, &auth) {
+		t.Fatalf("Expected ErrAuthenticationFailed but was %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package horcrux
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// An Alternate is a fragment's share encrypted under the key derived from
+// another accepted answer to its question. See WithAlternateAnswers.
+type Alternate struct {
+	Salt  []byte // Salt is the random salt used for key derivation.
+	Nonce []byte // Nonce is the random nonce used for encryption.
+	Value []byte // Value is the encrypted share.
+}
+
+// fragment returns a copy of f which uses the alternate's salt, nonce, and
+// encrypted share.
+func (alt Alternate) fragment(f Fragment) Fragment {
+	f.Salt, f.Nonce, f.Value = alt.Salt, alt.Nonce, alt.Value
+	return f
+}
+
+// addAlternate encrypts the share under the key derived from the given
+// normalized answer to question q, adding it to frag's alternates.
+func addAlternate(ctx context.Context, frag *Fragment, q, answer string, share []byte, cfg *splitConfig) error {
+	alt := Alternate{Salt: make([]byte, saltLen)}
+	if _, err := io.ReadFull(cfg.random(), alt.Salt); err != nil {
+		return err
+	}
+
+	key, err := deriveKeyContext(ctx, alt.fragment(*frag), answer)
+	if err != nil {
+		return err
+	}
+	defer zero(key)
+
+	aead, _, err := cfg.newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	alt.Nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(cfg.random(), alt.Nonce); err != nil {
+		return err
+	}
+
+	ad, err := frag.associatedData(q)
+	if err != nil {
+		return err
+	}
+
+	alt.Value = aead.Seal(nil, alt.Nonce, share, ad)
+	frag.Alternates = append(frag.Alternates, alt)
+	frag.StructureChecksum = frag.ComputeStructureChecksum()
+	return nil
+}
+
+// isAuthFailure returns true if err is an ErrAuthenticationFailed, which means
+// the answer was probably wrong.
+func isAuthFailure(err error) bool {
+	var authErr ErrAuthenticationFailed
+	return errors.As(err, &authErr)
+}
@@ -0,0 +1,91 @@
+package horcrux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAlternateAnswers(t *testing.T) {
+	q := map[string]string{
+		"Where were you born?":    "New York City",
+		"What's your pet's name?": "Spot",
+	}
+
+	frags, err := Split(secret, q, 2, 2<<10, 8, 1,
+		WithAlternateAnswers(map[string][]string{
+			"Where were you born?": {"NYC", "New York"},
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, answer := range []string{"New York City", "nyc", "New York"} {
+		answers := make([]Answer, len(frags))
+		for i, f := range frags {
+			if f.Question == "Where were you born?" {
+				if len(f.Alternates) != 2 {
+					t.Fatalf("Expected 2 alternates but was %d", len(f.Alternates))
+				}
+				answers[i] = f.WithAnswer(answer)
+			} else {
+				answers[i] = f.WithAnswer(q[f.Question])
+			}
+		}
+
+		s, err := Recover(answers)
+		if err != nil {
+			t.Fatalf("%q: %v", answer, err)
+		}
+
+		if string(s) != string(secret) {
+			t.Fatalf("Expected %q but was %q", secret, s)
+		}
+	}
+}
+
+func TestAlternateAnswersWrongAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAlternateAnswers(map[string][]string{
+			"What's your first pet's name?": {"Spotty"},
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.Question != "What's your first pet's name?" {
+			continue
+		}
+
+		_, err := DecryptShares([]Answer{f.WithAnswer("Rover")})
+		if !isAuthFailure(err) {
+			t.Fatalf("Expected an authentication failure but was %v", err)
+		}
+	}
+}
+
+func TestAlternateAnswersBinary(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAlternateAnswers(map[string][]string{
+			"What's your first pet's name?": {"Spotty", "Rover"},
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual Fragment
+		if err := actual.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual.Alternates, f.Alternates) {
+			t.Fatalf("Expected %v but was %v", f.Alternates, actual.Alternates)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package horcrux
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FragmentSet is the set of fragments produced by splitting a secret.
+type FragmentSet []Fragment
+
+// ArchivalFragmentSet is a fragment set with signed provenance metadata, for
+// long-term storage.
+type ArchivalFragmentSet struct {
+	FragmentSet
+
+	CreatedAt         time.Time // CreatedAt is when the set was archived.
+	CreatorID         string    // CreatorID identifies who archived the set.
+	SoftwareVersion   string    // SoftwareVersion identifies what archived it.
+	ArchivalSignature []byte    // ArchivalSignature signs all other fields.
+}
+
+// ErrBadArchiveSignature is returned when an archived fragment set's signature
+// is invalid.
+var ErrBadArchiveSignature = errors.New("horcrux: bad archive signature")
+
+// Archive records the current time, the given creator ID, and the software
+// version alongside the fragment set, and signs them all with the given key.
+func Archive(fs FragmentSet, creatorID string, priv ed25519.PrivateKey) (ArchivalFragmentSet, error) {
+	afs := ArchivalFragmentSet{
+		FragmentSet:     fs,
+		CreatedAt:       time.Now().UTC(),
+		CreatorID:       creatorID,
+		SoftwareVersion: fmt.Sprintf("horcrux/v%d", FragmentVersion2),
+	}
+
+	if len(priv) != ed25519.PrivateKeySize {
+		return afs, fmt.Errorf("horcrux: bad archive key length: %d", len(priv))
+	}
+
+	msg, err := afs.message()
+	if err != nil {
+		return afs, err
+	}
+
+	afs.ArchivalSignature = ed25519.Sign(priv, msg)
+	return afs, nil
+}
+
+// VerifyArchive returns ErrBadArchiveSignature if the archived fragment set's
+// signature is not valid for the given public key.
+func VerifyArchive(afs ArchivalFragmentSet, pub ed25519.PublicKey) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("horcrux: bad archive key length: %d", len(pub))
+	}
+
+	msg, err := afs.message()
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(pub, msg, afs.ArchivalSignature) {
+		return ErrBadArchiveSignature
+	}
+	return nil
+}
+
+// message returns the message signed to archive the fragment set, which
+// contains the archival metadata and the binary encoding of each fragment.
+func (afs ArchivalFragmentSet) message() ([]byte, error) {
+	msg := []byte("horcrux archive")
+	msg = binary.BigEndian.AppendUint64(msg, uint64(afs.CreatedAt.UnixNano()))
+
+	fields := [][]byte{[]byte(afs.CreatorID), []byte(afs.SoftwareVersion)}
+	for _, f := range afs.FragmentSet {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, b)
+	}
+
+	for _, b := range fields {
+		msg = binary.BigEndian.AppendUint32(msg, uint32(len(b)))
+		msg = append(msg, b...)
+	}
+	return msg, nil
+}
@@ -0,0 +1,72 @@
+package horcrux
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+)
+
+func archive(t *testing.T) (ArchivalFragmentSet, ed25519.PublicKey) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	afs, err := Archive(frags, "alice@example.com", priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return afs, pub
+}
+
+func TestVerifyArchive(t *testing.T) {
+	afs, pub := archive(t)
+
+	if err := VerifyArchive(afs, pub); err != nil {
+		t.Fatal(err)
+	}
+
+	if afs.CreatorID != "alice@example.com" {
+		t.Errorf("Expected %v but was %v", "alice@example.com", afs.CreatorID)
+	}
+
+	if afs.SoftwareVersion != "horcrux/v2" {
+		t.Errorf("Expected %v but was %v", "horcrux/v2", afs.SoftwareVersion)
+	}
+}
+
+func TestVerifyArchiveModifiedCreatedAt(t *testing.T) {
+	afs, pub := archive(t)
+	afs.CreatedAt = afs.CreatedAt.Add(-time.Hour)
+
+	if err := VerifyArchive(afs, pub); err != ErrBadArchiveSignature {
+		t.Fatalf("Expected %v but was %v", ErrBadArchiveSignature, err)
+	}
+}
+
+func TestVerifyArchiveModifiedFragment(t *testing.T) {
+	afs, pub := archive(t)
+	afs.FragmentSet[0].Question = "What's your favorite color?"
+
+	if err := VerifyArchive(afs, pub); err != ErrBadArchiveSignature {
+		t.Fatalf("Expected %v but was %v", ErrBadArchiveSignature, err)
+	}
+}
+
+func TestVerifyArchiveWrongKey(t *testing.T) {
+	afs, _ := archive(t)
+
+	pub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyArchive(afs, pub); err != ErrBadArchiveSignature {
+		t.Fatalf("Expected %v but was %v", ErrBadArchiveSignature, err)
+	}
+}
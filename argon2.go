@@ -0,0 +1,48 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codahale/chacha20"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// MinArgon2Memory is the smallest Argon2id memory parameter, in KiB,
+	// accepted for key derivation.
+	MinArgon2Memory = 8
+
+	// MaxArgon2Threads is the largest Argon2id parallelism parameter.
+	MaxArgon2Threads = 255
+)
+
+// argon2Key derives a 256-bit key from the given password using Argon2id with
+// the given time, memory (in KiB), and parallelism parameters.
+func argon2Key(password, salt []byte, timeCost, memory, threads int) ([]byte, error) {
+	if err := checkArgon2Params(timeCost, memory, threads); err != nil {
+		return nil, err
+	}
+
+	return argon2.IDKey(password, salt, uint32(timeCost), uint32(memory),
+		uint8(threads), chacha20.KeySize), nil
+}
+
+// checkArgon2Params returns an error if the parameters are out of range.
+func checkArgon2Params(timeCost, memory, threads int) error {
+	if timeCost < 1 {
+		return errors.New("horcrux: argon2id time must be at least 1")
+	}
+
+	if threads < 1 || threads > MaxArgon2Threads {
+		return fmt.Errorf("horcrux: argon2id threads must be between 1 and %d",
+			MaxArgon2Threads)
+	}
+
+	if memory < MinArgon2Memory*threads || int64(memory) > int64(^uint32(0)) {
+		return fmt.Errorf("horcrux: argon2id memory must be at least %d KiB per thread",
+			MinArgon2Memory)
+	}
+
+	return nil
+}
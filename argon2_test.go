@@ -0,0 +1,54 @@
+package horcrux
+
+import "testing"
+
+func TestSplitRecoverArgon2id(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0, WithArgon2id(1, 64, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.KDF != KDFArgon2id || f.Argon2Time != 1 || f.Argon2Memory != 64 ||
+			f.Argon2Threads != 1 || f.N != 0 {
+			t.Fatalf("Expected an Argon2id fragment but was %v", f)
+		}
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		b, err := frags[i].MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var f Fragment
+		if err := f.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestSplitBadArgon2idParams(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0, WithArgon2id(1, 64, 0))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := "horcrux: key derivation failed: argon2id threads must be between 1 and 255"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
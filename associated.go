@@ -0,0 +1,47 @@
+package horcrux
+
+// associatedData returns the associated data used to encrypt the fragment's
+// share, given its plaintext question. Fragments with bound parameters use the
+// binary encoding of the hash of the question and every field used to derive
+// the key or combine the share, so that swapping the question or downgrading
+// the KDF parameters makes decryption fail. Older fragments use the hash of the
+// question if it is private, and nothing otherwise.
+func (f Fragment) associatedData(question string) ([]byte, error) {
+	if !f.BoundParams {
+		if f.QuestionIsHashed {
+			return hashQuestion(question), nil
+		}
+		return nil, nil
+	}
+
+	// versions and the cipher's absence don't change the key or cipher used
+	alg, _ := parseKDF(f.KDF)
+	cipher := f.Cipher
+	if cipher == "" {
+		cipher = CipherChaCha20Poly1305
+	}
+
+	s := Fragment{
+		Version:        f.Version,
+		ID:             f.ID,
+		SetID:          f.SetID,
+		Weight:         f.Weight,
+		Group:          f.Group,
+		GroupThreshold: f.GroupThreshold,
+		Required:       f.Required,
+		K:              f.K,
+		N:              f.N,
+		R:              f.R,
+		P:              f.P,
+		KDF:            alg,
+		BcryptCost:     f.BcryptCost,
+		Argon2Time:     f.Argon2Time,
+		Argon2Memory:   f.Argon2Memory,
+		Argon2Threads:  f.Argon2Threads,
+		KDFParams:      f.KDFParams,
+		Cipher:         cipher,
+		Question:       string(hashQuestion(question)),
+		BoundParams:    true,
+	}
+	return s.MarshalBinary()
+}
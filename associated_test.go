@@ -0,0 +1,99 @@
+package horcrux
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/codahale/chacha20poly1305"
+)
+
+// unbind re-encrypts the fragment's share without binding its question and
+// parameters, like fragments written before they were bound.
+func unbind(t *testing.T, f Fragment, answer string) Fragment {
+	v := decryptShare(t, f, answer)
+
+	if normalize, ok := LookupNormalizer(f.NormalizerID); ok {
+		answer = normalize(answer)
+	}
+
+	k, err := deriveKey(f, answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead, err := chacha20poly1305.New(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.BoundParams = false
+	f.Value = aead.Seal(nil, f.Nonce, v, nil)
+	f.Checksum = f.ComputeChecksum()
+	f.StructureChecksum = f.ComputeStructureChecksum()
+	return f
+}
+
+func TestSplitBindsParams(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if !f.BoundParams {
+			t.Fatalf("Fragment %d doesn't bind its parameters", f.ID)
+		}
+	}
+}
+
+func TestRecoverTamperedParams(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := map[string]func(*Fragment){
+		"question": func(f *Fragment) { f.Question = "What's your favorite color?" },
+		"ID":       func(f *Fragment) { f.ID = 9 },
+		"N":        func(f *Fragment) { f.N = 2 << 9 },
+		"R":        func(f *Fragment) { f.R = 4 },
+		"unbound":  func(f *Fragment) { f.BoundParams = false },
+	}
+
+	for name, fn := range tamper {
+		a := frags[0].WithAnswer(questions[frags[0].Question])
+		fn(&a.Fragment)
+		a.StructureChecksum = a.ComputeStructureChecksum()
+
+		_, err := Recover([]Answer{a, frags[1].WithAnswer(questions[frags[1].Question])})
+
+		var wrong ErrWrongAnswer
+		if !errors.As(err, &wrong) {
+			t.Fatalf("Expected a wrong answer with a tampered %s but was %v",
+				name, err)
+		}
+	}
+}
+
+func TestRecoverUnboundParams(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i, f := range frags[:2] {
+		answer := questions[f.Question]
+		answers[i] = unbind(t, f, answer).WithAnswer(answer)
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+}
@@ -0,0 +1,33 @@
+package horcrux
+
+import "fmt"
+
+// ErrTooManyAttempts is returned when a fragment which has reached its maximum
+// number of answer attempts is used for recovery. No key is derived.
+type ErrTooManyAttempts struct {
+	ID int // ID is the ID of the fragment.
+}
+
+func (e ErrTooManyAttempts) Error() string {
+	return fmt.Sprintf("horcrux: too many attempts for fragment %d", e.ID)
+}
+
+// IncrementAttempt returns a copy of the fragment with one more failed attempt
+// recorded. Attempts are only counted if the caller stores the returned
+// fragment in place of the original, so they should be protected with an HMAC
+// (see WithFragmentHMACKey) to stop them from being reset. The HMAC covers the
+// attempt count, so the returned fragment's HMAC no longer verifies: recompute
+// it with ComputeHMAC before storing the fragment.
+func IncrementAttempt(f Fragment) Fragment {
+	f = f.Clone()
+	f.AttemptCount++
+	return f
+}
+
+// ResetAttempts returns a copy of the fragment with no failed attempts. As with
+// IncrementAttempt, the returned fragment's HMAC must be recomputed.
+func ResetAttempts(f Fragment) Fragment {
+	f = f.Clone()
+	f.AttemptCount = 0
+	return f
+}
@@ -0,0 +1,122 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTooManyAttempts(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.MaxAttempts = 3
+
+	for i := 0; i < f.MaxAttempts; i++ {
+		if _, err := DecryptShares([]Answer{f.WithAnswer("wrong")}); err == nil {
+			t.Fatal("Expected error but got none")
+		}
+		f = IncrementAttempt(f)
+	}
+
+	derived := false
+	old := derive
+	defer func() { derive = old }()
+	derive = func(f Fragment, answer string) ([]byte, error) {
+		derived = true
+		return old(f, answer)
+	}
+
+	_, err = DecryptShares([]Answer{f.WithAnswer(questions[f.Question])})
+
+	expected := ErrTooManyAttempts{ID: int(f.ID)}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+
+	if derived {
+		t.Fatal("Expected no key derivation but one was attempted")
+	}
+}
+
+func TestResetAttempts(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.MaxAttempts = 1
+	f = ResetAttempts(IncrementAttempt(f))
+
+	answers := []Answer{
+		f.WithAnswer(questions[f.Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestIncrementAttemptWithHMAC(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := []byte("hmac key")
+	for i := range frags {
+		frags[i].MaxAttempts = 2
+		frags[i].HMAC = frags[i].ComputeHMAC(key)
+	}
+
+	f := IncrementAttempt(frags[0])
+	if f.VerifyHMAC(key) {
+		t.Fatal("Expected the HMAC to fail after incrementing the attempt count")
+	}
+
+	f.HMAC = f.ComputeHMAC(key)
+
+	answers := []Answer{
+		f.WithAnswer(questions[f.Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	actual, err := Recover(answers, WithFragmentHMACKey(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+
+	f = IncrementAttempt(f)
+	f.HMAC = f.ComputeHMAC(key)
+
+	_, err = DecryptShares([]Answer{f.WithAnswer(questions[f.Question])},
+		WithFragmentHMACKey(key))
+
+	expected := ErrTooManyAttempts{ID: int(f.ID)}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+
+	// resetting the count without the key is caught
+	reset := ResetAttempts(f)
+	_, err = DecryptShares([]Answer{reset.WithAnswer(questions[f.Question])},
+		WithFragmentHMACKey(key))
+
+	corrupt := ErrCorruptFragment{ID: int(f.ID), Reason: "failed HMAC verification"}
+	if err != corrupt {
+		t.Fatalf("Expected %v but was %v", corrupt, err)
+	}
+}
@@ -0,0 +1,30 @@
+package horcrux
+
+import (
+	"context"
+
+	"github.com/codahale/chacha20poly1305"
+)
+
+// DecryptionBackend decrypts shares during recovery. A backend could, for
+// example, hand the decryption off to a hardware or cloud key management
+// service. Recover calls backends concurrently.
+type DecryptionBackend interface {
+	// AEADDecrypt decrypts and authenticates the ciphertext using
+	// ChaCha20Poly1305 with the given nonce, key, and associated data. The
+	// key is zeroed once AEADDecrypt returns, so it must be copied if kept.
+	AEADDecrypt(ctx context.Context, ciphertext, nonce, key, aad []byte) ([]byte, error)
+}
+
+// DefaultDecryptionBackend decrypts shares in-process.
+var DefaultDecryptionBackend DecryptionBackend = localBackend{}
+
+type localBackend struct{}
+
+func (localBackend) AEADDecrypt(ctx context.Context, ciphertext, nonce, key, aad []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
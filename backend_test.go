@@ -0,0 +1,63 @@
+package horcrux
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+)
+
+type recordingBackend struct {
+	sync.Mutex
+	keys [][]byte
+}
+
+func (b *recordingBackend) AEADDecrypt(ctx context.Context, ciphertext, nonce, key, aad []byte) ([]byte, error) {
+	b.Lock()
+	b.keys = append(b.keys, cloneBytes(key))
+	b.Unlock()
+	return DefaultDecryptionBackend.AEADDecrypt(ctx, ciphertext, nonce, key, aad)
+}
+
+func TestRecoverDecryptionBackend(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	b := &recordingBackend{}
+	s, err := Recover(answers, WithDecryptionBackend(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+
+	if len(b.keys) != len(answers) {
+		t.Fatalf("Expected %d calls but was %d", len(answers), len(b.keys))
+	}
+
+	// keys are derived in parallel, so may reach the backend in any order
+	for _, a := range answers {
+		expected, err := deriveKey(a.Fragment, FoldNormalizer(a.Answer))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := false
+		for _, k := range b.keys {
+			found = found || bytes.Equal(k, expected)
+		}
+
+		if !found {
+			t.Fatalf("Expected key %x but was missing from %x", expected, b.keys)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package horcrux
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"golang.org/x/crypto/blowfish"
+)
+
+const (
+	// MinBcryptCost is the smallest bcrypt cost accepted for key derivation.
+	MinBcryptCost = 10
+
+	// MaxBcryptCost is the largest bcrypt cost accepted for key derivation.
+	MaxBcryptCost = 31
+
+	bcryptSaltLen = 16
+)
+
+var magicCipherData = []byte("OrpheanBeholderScryDoubt")
+
+// bcryptKey derives a 256-bit key from the given password by hashing bcrypt's
+// raw 192-bit output with SHA-256, using the first 16 bytes of the salt.
+func bcryptKey(password, salt []byte, cost int) ([]byte, error) {
+	if err := checkBcryptCost(cost); err != nil {
+		return nil, err
+	}
+
+	if len(salt) < bcryptSaltLen {
+		return nil, fmt.Errorf("horcrux: bcrypt salt must be at least %d bytes",
+			bcryptSaltLen)
+	}
+
+	out, err := bcryptRaw(password, salt[:bcryptSaltLen], cost)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(out)
+
+	k := sha256.Sum256(out)
+	return k[:], nil
+}
+
+// bcryptRaw returns bcrypt's raw 192-bit output for the given password, 16-byte
+// salt, and cost, before it is truncated and encoded as a bcrypt hash.
+// golang.org/x/crypto/bcrypt only produces encoded hashes with random salts, so
+// the bcrypt core is built here on top of golang.org/x/crypto/blowfish, as
+// golang.org/x/crypto/bcrypt builds it.
+func bcryptRaw(password, salt []byte, cost int) ([]byte, error) {
+	key := make([]byte, len(password)+1)
+	copy(key, password)
+	defer zero(key)
+
+	c, err := blowfish.NewSaltedCipher(key, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < 1<<uint(cost); i++ {
+		blowfish.ExpandKey(key, c)
+		blowfish.ExpandKey(salt, c)
+	}
+
+	out := make([]byte, len(magicCipherData))
+	copy(out, magicCipherData)
+	for i := 0; i < len(out); i += 8 {
+		for j := 0; j < 64; j++ {
+			c.Encrypt(out[i:i+8], out[i:i+8])
+		}
+	}
+	return out, nil
+}
+
+// checkBcryptCost returns an error if the cost is out of range.
+func checkBcryptCost(cost int) error {
+	if cost < MinBcryptCost || cost > MaxBcryptCost {
+		return fmt.Errorf("horcrux: bcrypt cost must be between %d and %d",
+			MinBcryptCost, MaxBcryptCost)
+	}
+	return nil
+}
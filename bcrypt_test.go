@@ -0,0 +1,86 @@
+package horcrux
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestSplitRecoverBcrypt(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0, WithBcrypt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.KDF != KDFBcrypt || f.BcryptCost != 10 || f.N != 0 {
+			t.Fatalf("Expected a bcrypt fragment but was %v", f)
+		}
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = Answer{
+			Fragment: frags[i],
+			Answer:   questions[frags[i].Question],
+		}
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestSplitBadBcryptCost(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0, WithBcrypt(9))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := "horcrux: key derivation failed: bcrypt cost must be between 10 and 31"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+// bcryptEncoding is the base64 encoding used in bcrypt hashes.
+var bcryptEncoding = base64.NewEncoding(
+	"./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").
+	WithPadding(base64.NoPadding)
+
+func TestBcryptKnownAnswers(t *testing.T) {
+	// published bcrypt test vectors, as used by OpenBSD, OpenWall, and
+	// golang.org/x/crypto/bcrypt
+	vectors := []struct {
+		password     string
+		cost         int
+		salt, digest string
+	}{
+		{"U*U", 5, "CCCCCCCCCCCCCCCCCCCCC.", "E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"},
+		{"", 6, "DCq7YPn5Rq63x1Lad4cll.", "TV4S6ytwfsfvkgY8jIucDrjc8deX1s."},
+		{"a", 6, "m0CrhHm10qJ3lXRY.5zDGO", "3rS2KdeeWLuGmsfGlMfOxih58VYVfxe"},
+		{"abc", 6, "If6bvum7DFjUnE9p2uDeDu", "0YHzrHM6tf.iqN8.yx.jNN1ILEf7h0i"},
+	}
+
+	for _, v := range vectors {
+		salt, err := bcryptEncoding.DecodeString(v.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := bcryptRaw([]byte(v.password), salt, v.cost)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual := bcryptEncoding.EncodeToString(out[:23])
+		if actual != v.digest {
+			t.Fatalf("Expected %v but was %v", v.digest, actual)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package horcrux
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// The binary encoding of a fragment is its version byte followed by a sequence
+// of fields. Each field is a tag byte, the uvarint length of the field's value,
+// and the value itself. Integers are encoded as varints, strings and byte
+// slices as-is, and booleans as a single byte. Each metadata entry is its own
+// field, containing the uvarint length of the key, the key, and the value.
+// Empty fields are omitted, and unknown tags are skipped so that older readers
+// can read newer fragments. Version 1 fragments have no KDF, bcrypt cost,
+// Argon2id, or KDF parameter fields, as they always use scrypt.
+
+const (
+	tagID = iota + 1
+	tagK
+	tagN
+	tagR
+	tagP
+	tagBcryptCost
+	tagKDF
+	tagQuestion
+	tagAnswerHint
+	tagNonce
+	tagSalt
+	tagValue
+	tagEscrowedAnswer
+	tagRevoked
+	tagRevocationSignature
+	tagQuestionIsHashed
+	tagMetadata
+	tagHMAC
+	tagNormalizerID
+	tagChecksum
+	tagAnswerSeparator
+	tagMaxAttempts
+	tagAttemptCount
+	tagArgon2Time
+	tagArgon2Memory
+	tagArgon2Threads
+	tagKDFParams
+	tagCipher
+	tagLocale
+	tagAlternate
+	tagStructureChecksum
+	tagSetID
+	tagWeight
+	tagGroup
+	tagGroupThreshold
+	tagRequired
+	tagBoundParams
+)
+
+var errTruncated = errors.New("horcrux: truncated fragment")
+
+// MarshalBinary returns the fragment's compact binary encoding.
+func (f Fragment) MarshalBinary() ([]byte, error) {
+	version := f.version()
+	b := []byte{version}
+
+	// write the structure checksum first, so that truncating the encoding
+	// can't remove it
+	b = appendBytes(b, tagStructureChecksum, f.StructureChecksum)
+	b = appendInt(b, tagID, int(f.ID))
+	b = appendInt(b, tagK, f.K)
+	b = appendInt(b, tagN, f.N)
+	b = appendInt(b, tagR, f.R)
+	b = appendInt(b, tagP, f.P)
+
+	switch version {
+	case FragmentVersion1:
+		if alg, _ := parseKDF(f.KDF); alg != KDFScrypt || f.BcryptCost != 0 {
+			return nil, fmt.Errorf(
+				"horcrux: version %d fragments only support scrypt", version)
+		}
+	case FragmentVersion2:
+		b = appendInt(b, tagBcryptCost, f.BcryptCost)
+		b = appendBytes(b, tagKDF, []byte(f.KDF))
+		b = appendInt(b, tagArgon2Time, f.Argon2Time)
+		b = appendInt(b, tagArgon2Memory, f.Argon2Memory)
+		b = appendInt(b, tagArgon2Threads, f.Argon2Threads)
+		b = appendBytes(b, tagKDFParams, f.KDFParams)
+	default:
+		return nil, fmt.Errorf("horcrux: unknown fragment version %d", version)
+	}
+
+	b = appendBytes(b, tagQuestion, []byte(f.Question))
+	b = appendBytes(b, tagAnswerHint, []byte(f.AnswerHint))
+	b = appendBytes(b, tagNonce, f.Nonce)
+	b = appendBytes(b, tagSalt, f.Salt)
+	b = appendBytes(b, tagValue, f.Value)
+	b = appendBytes(b, tagEscrowedAnswer, f.EscrowedAnswer)
+	b = appendBool(b, tagRevoked, f.Revoked)
+	b = appendBytes(b, tagRevocationSignature, f.RevocationSignature)
+	b = appendBool(b, tagQuestionIsHashed, f.QuestionIsHashed)
+	b = appendBytes(b, tagNormalizerID, []byte(f.NormalizerID))
+	b = appendBytes(b, tagAnswerSeparator, []byte(f.AnswerSeparator))
+	b = appendInt(b, tagMaxAttempts, f.MaxAttempts)
+	b = appendInt(b, tagAttemptCount, f.AttemptCount)
+	b = appendBytes(b, tagCipher, []byte(f.Cipher))
+	b = appendBytes(b, tagLocale, []byte(f.Locale))
+	b = appendBytes(b, tagSetID, f.SetID)
+	b = appendInt(b, tagWeight, f.Weight)
+	b = appendInt(b, tagGroup, f.Group)
+	b = appendInt(b, tagGroupThreshold, f.GroupThreshold)
+	b = appendBool(b, tagRequired, f.Required)
+	b = appendBool(b, tagBoundParams, f.BoundParams)
+	for _, alt := range f.Alternates {
+		e := binary.AppendUvarint(nil, uint64(len(alt.Salt)))
+		e = append(e, alt.Salt...)
+		e = binary.AppendUvarint(e, uint64(len(alt.Nonce)))
+		e = append(e, alt.Nonce...)
+		e = append(e, alt.Value...)
+		b = appendBytes(b, tagAlternate, e)
+	}
+	if f.Checksum != [4]byte{} {
+		b = appendBytes(b, tagChecksum, f.Checksum[:])
+	}
+
+	keys := make([]string, 0, len(f.Metadata))
+	for k := range f.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e := binary.AppendUvarint(nil, uint64(len(k)))
+		e = append(e, k...)
+		e = append(e, f.Metadata[k]...)
+		b = appendBytes(b, tagMetadata, e)
+	}
+
+	b = appendBytes(b, tagHMAC, f.HMAC)
+	return b, nil
+}
+
+// UnmarshalBinary decodes the fragment from its compact binary encoding.
+func (f *Fragment) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errTruncated
+	}
+
+	var v Fragment
+	var err error
+	switch data[0] {
+	case FragmentVersion1:
+		err = decodeFields(&v, data[1:], false)
+	case FragmentVersion2:
+		err = decodeFields(&v, data[1:], true)
+	default:
+		err = fmt.Errorf("horcrux: unknown fragment encoding version %d",
+			data[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	v.Version = data[0]
+	*f = v
+	return nil
+}
+
+// decodeFields decodes the sequence of fields which follows the version byte
+// into v.
+// The KDF, bcrypt cost, Argon2id, and KDF parameter fields are only decoded if
+// hasKDF is true.
+func decodeFields(v *Fragment, data []byte, hasKDF bool) error {
+	for len(data) > 0 {
+		tag := data[0]
+		n, l := binary.Uvarint(data[1:])
+		if l <= 0 || n > uint64(len(data)-1-l) {
+			return errTruncated
+		}
+		value := data[1+l : 1+l+int(n)]
+		data = data[1+l+int(n):]
+
+		var err error
+		switch tag {
+		case tagID:
+			var id int
+			id, err = decodeInt(value)
+			if id < 0 || id > 255 {
+				err = fmt.Errorf("horcrux: bad fragment ID %d", id)
+			}
+			v.ID = byte(id)
+		case tagK:
+			v.K, err = decodeInt(value)
+		case tagN:
+			v.N, err = decodeInt(value)
+		case tagR:
+			v.R, err = decodeInt(value)
+		case tagP:
+			v.P, err = decodeInt(value)
+		case tagBcryptCost:
+			if hasKDF {
+				v.BcryptCost, err = decodeInt(value)
+			}
+		case tagKDF:
+			if hasKDF {
+				v.KDF = string(value)
+			}
+		case tagQuestion:
+			v.Question = string(value)
+		case tagAnswerHint:
+			v.AnswerHint = string(value)
+		case tagNonce:
+			v.Nonce = cloneBytes(value)
+		case tagSalt:
+			v.Salt = cloneBytes(value)
+		case tagValue:
+			v.Value = cloneBytes(value)
+		case tagEscrowedAnswer:
+			v.EscrowedAnswer = cloneBytes(value)
+		case tagRevoked:
+			v.Revoked = decodeBool(value)
+		case tagRevocationSignature:
+			v.RevocationSignature = cloneBytes(value)
+		case tagQuestionIsHashed:
+			v.QuestionIsHashed = decodeBool(value)
+		case tagMetadata:
+			n, l := binary.Uvarint(value)
+			if l <= 0 || n > uint64(len(value)-l) {
+				return errTruncated
+			}
+			if v.Metadata == nil {
+				v.Metadata = make(map[string]string)
+			}
+			k := string(value[l : l+int(n)])
+			v.Metadata[k] = string(value[l+int(n):])
+		case tagHMAC:
+			v.HMAC = cloneBytes(value)
+		case tagNormalizerID:
+			v.NormalizerID = string(value)
+		case tagAnswerSeparator:
+			v.AnswerSeparator = string(value)
+		case tagMaxAttempts:
+			v.MaxAttempts, err = decodeInt(value)
+		case tagAttemptCount:
+			v.AttemptCount, err = decodeInt(value)
+		case tagArgon2Time:
+			if hasKDF {
+				v.Argon2Time, err = decodeInt(value)
+			}
+		case tagArgon2Memory:
+			if hasKDF {
+				v.Argon2Memory, err = decodeInt(value)
+			}
+		case tagArgon2Threads:
+			if hasKDF {
+				v.Argon2Threads, err = decodeInt(value)
+			}
+		case tagKDFParams:
+			if hasKDF {
+				v.KDFParams = cloneBytes(value)
+			}
+		case tagCipher:
+			v.Cipher = string(value)
+		case tagLocale:
+			v.Locale = string(value)
+		case tagStructureChecksum:
+			v.StructureChecksum = cloneBytes(value)
+		case tagSetID:
+			v.SetID = cloneBytes(value)
+		case tagWeight:
+			v.Weight, err = decodeInt(value)
+		case tagGroup:
+			v.Group, err = decodeInt(value)
+		case tagGroupThreshold:
+			v.GroupThreshold, err = decodeInt(value)
+		case tagRequired:
+			v.Required = decodeBool(value)
+		case tagBoundParams:
+			v.BoundParams = decodeBool(value)
+		case tagAlternate:
+			var alt Alternate
+			for _, p := range []*[]byte{&alt.Salt, &alt.Nonce} {
+				n, l := binary.Uvarint(value)
+				if l <= 0 || n > uint64(len(value)-l) {
+					return errTruncated
+				}
+				*p = cloneBytes(value[l : l+int(n)])
+				value = value[l+int(n):]
+			}
+			alt.Value = cloneBytes(value)
+			v.Alternates = append(v.Alternates, alt)
+		case tagChecksum:
+			if len(value) != len(v.Checksum) {
+				return errors.New("horcrux: bad checksum in fragment")
+			}
+			copy(v.Checksum[:], value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendInt(b []byte, tag byte, n int) []byte {
+	if n == 0 {
+		return b
+	}
+	return appendBytes(b, tag, binary.AppendVarint(nil, int64(n)))
+}
+
+func appendBool(b []byte, tag byte, v bool) []byte {
+	if !v {
+		return b
+	}
+	return appendBytes(b, tag, []byte{1})
+}
+
+func appendBytes(b []byte, tag byte, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = append(b, tag)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func decodeBool(b []byte) bool {
+	return len(b) == 1 && b[0] == 1
+}
+
+func decodeInt(b []byte) (int, error) {
+	n, l := binary.Varint(b)
+	if l != len(b) || int64(int(n)) != n {
+		return 0, errors.New("horcrux: bad integer in fragment")
+	}
+	return int(n), nil
+}
@@ -0,0 +1,183 @@
+package horcrux
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"reflect"
+	"testing"
+)
+
+func TestFragmentBinary(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAnswerHint("What's your first pet's name?", "SP"),
+		WithAnswerEscrow(bytes.Repeat([]byte{7}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := RevokeFragment(frags[0], priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := frags[1].Clone()
+	meta.Metadata = map[string]string{"owner": "alice", "": "empty key"}
+
+	for _, f := range append(frags, revoked, meta) {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual Fragment
+		if err := actual.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, f) {
+			t.Fatalf("Expected %v but was %v", f, actual)
+		}
+	}
+}
+
+func TestFragmentBinaryAllFields(t *testing.T) {
+	// set every field, so that new fields can't be left out of the encoding
+	var f Fragment
+	v := reflect.ValueOf(&f).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Int:
+			field.SetInt(int64(i + 1))
+		case reflect.Uint8:
+			field.SetUint(uint64(i + 1))
+		case reflect.String:
+			field.SetString(v.Type().Field(i).Name)
+		case reflect.Bool:
+			field.SetBool(true)
+		case reflect.Slice:
+			if field.Type() == reflect.TypeOf([]Alternate{}) {
+				b := []byte{byte(i), 1, 2}
+				field.Set(reflect.ValueOf([]Alternate{{b, b, b}}))
+				continue
+			}
+			field.SetBytes([]byte{byte(i), 1, 2})
+		case reflect.Array:
+			reflect.Copy(field, reflect.ValueOf([]byte{byte(i), 1, 2, 3}))
+		case reflect.Map:
+			field.Set(reflect.ValueOf(map[string]string{"k": "v"}))
+		default:
+			t.Fatalf("Unexpected field %v", v.Type().Field(i).Name)
+		}
+	}
+	f.Version = FragmentVersion2
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual Fragment
+	if err := actual.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, f) {
+		t.Fatalf("Expected %v but was %v", f, actual)
+	}
+}
+
+func TestFragmentBinarySkipsUnknownFields(t *testing.T) {
+	f := Fragment{
+		Version:  FragmentVersion2,
+		ID:       1,
+		K:        2,
+		Question: "Q",
+		Value:    []byte{1, 2, 3},
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = append(b, 200, 2, 9, 9)
+
+	var actual Fragment
+	if err := actual.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, f) {
+		t.Fatalf("Expected %v but was %v", f, actual)
+	}
+}
+
+func TestFragmentBinaryTruncated(t *testing.T) {
+	f := Fragment{ID: 1, K: 2, Question: "Q", Value: []byte{1, 2, 3}}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual Fragment
+	err = actual.UnmarshalBinary(b[:len(b)-1])
+	if err != errTruncated {
+		t.Fatalf("Expected %v but was %v", errTruncated, err)
+	}
+}
+
+func TestFragmentBinaryBadVersion(t *testing.T) {
+	var f Fragment
+	err := f.UnmarshalBinary([]byte{99})
+	if err == nil {
+		t.Fatalf("Expected error but got %v", f)
+	}
+
+	expected := "horcrux: unknown fragment encoding version 99"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestSplitMaxFragmentSize(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithMaxFragmentSize(512))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(b) > 512 {
+			t.Fatalf("Expected at most 512 bytes but was %d", len(b))
+		}
+	}
+}
+
+func TestSplitFragmentTooLarge(t *testing.T) {
+	frags, err := Split(bytes.Repeat([]byte{'x'}, 250), questions, 2, 2<<10, 8, 1,
+		WithMaxFragmentSize(64))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	e, ok := err.(ErrFragmentTooLarge)
+	if !ok {
+		t.Fatalf("Expected ErrFragmentTooLarge but was %v", err)
+	}
+
+	if e.Size <= 64 {
+		t.Fatalf("Expected size > 64 but was %d", e.Size)
+	}
+}
@@ -0,0 +1,65 @@
+package horcrux
+
+import (
+	"context"
+	"fmt"
+)
+
+// BlindDecrypt decrypts the fragment's share with the given answer and returns
+// it XORed with the given mask, which must be as long as the share. The mask
+// is chosen by whoever will combine the shares and is given to each party out
+// of band, so that a party relaying its blinded share doesn't reveal the share
+// itself to anyone without the mask.
+//
+// This is a procedural separation for organizational policies, not secure
+// multi-party computation: whoever holds the masks and unblinds the shares
+// sees every share, and the party decrypting a fragment briefly holds its
+// share in memory.
+func BlindDecrypt(f Fragment, answer string, mask []byte) ([]byte, error) {
+	var cfg recoverConfig
+	s, err := openShare(context.Background(), f.WithAnswer(answer), &cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(s.value)
+
+	if len(mask) != len(s.value) {
+		return nil, errMaskLength(len(mask), len(s.value))
+	}
+
+	return Unblind(s.value, mask)
+}
+
+// Unblind returns the blinded share XORed with the mask it was blinded with,
+// which must be as long as the share.
+func Unblind(blindedShare, mask []byte) ([]byte, error) {
+	if len(mask) != len(blindedShare) {
+		return nil, errMaskLength(len(mask), len(blindedShare))
+	}
+
+	b := make([]byte, len(blindedShare))
+	for i := range b {
+		b[i] = blindedShare[i] ^ mask[i]
+	}
+	return b, nil
+}
+
+func errMaskLength(mask, share int) error {
+	return fmt.Errorf("horcrux: mask is %d bytes but share is %d bytes", mask, share)
+}
+
+// BlindCombine combines shares from BlindDecrypt, keyed by fragment ID, which
+// have been unblinded with Unblind. Returns ErrCombineFailed if there are no
+// shares or they aren't all the same length. Shares which are still blinded
+// will combine into garbage.
+func BlindCombine(blindedShares map[int][]byte) ([]byte, error) {
+	shares := make([]share, 0, len(blindedShares))
+	for id, v := range blindedShares {
+		if id < 1 || id > 255 {
+			return nil, fmt.Errorf("horcrux: bad share ID %d", id)
+		}
+		shares = append(shares, share{id: byte(id), value: v})
+	}
+	// the threshold isn't known, so only the shares' consistency is checked
+	return safeCombine(shares, 1)
+}
@@ -0,0 +1,93 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/codahale/sss"
+)
+
+func TestBlindCombine(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	masks := map[int][]byte{
+		int(frags[0].ID): bytes.Repeat([]byte{0x5a}, len(secret)),
+		int(frags[1].ID): bytes.Repeat([]byte{0xa5}, len(secret)),
+	}
+
+	blinded := make(map[int][]byte)
+	unblinded := make(map[int][]byte)
+	for _, f := range frags[:2] {
+		b, err := BlindDecrypt(f, questions[f.Question], masks[int(f.ID)])
+		if err != nil {
+			t.Fatal(err)
+		}
+		blinded[int(f.ID)] = b
+		unblinded[int(f.ID)], err = Unblind(b, masks[int(f.ID)])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	actual, err := BlindCombine(unblinded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+
+	m := make(map[byte][]byte, len(blinded))
+	for id, b := range blinded {
+		m[byte(id)] = b
+	}
+
+	if garbage := sss.Combine(m); bytes.Equal(garbage, secret) {
+		t.Fatal("Expected blinded shares not to combine into the secret")
+	}
+}
+
+func TestBlindDecryptBadMask(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = BlindDecrypt(frags[0], questions[frags[0].Question], []byte{1})
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestUnblindShortMask(t *testing.T) {
+	_, err := Unblind([]byte{1, 2, 3}, []byte{1})
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	expected := "horcrux: mask is 1 bytes but share is 3 bytes"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestBlindCombineInconsistentShares(t *testing.T) {
+	s, err := BlindCombine(map[int][]byte{1: {1, 2, 3}, 2: {4, 5}})
+
+	expected := ErrCombineFailed{Shares: 2, K: 1}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v (%v)", expected, err, s)
+	}
+
+	s, err = BlindCombine(map[int][]byte{})
+
+	expected = ErrCombineFailed{Shares: 0, K: 1}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v (%v)", expected, err, s)
+	}
+}
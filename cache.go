@@ -0,0 +1,154 @@
+package horcrux
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"sync"
+)
+
+// KDFCache caches keys derived from answers, so that repeatedly recovering a
+// secret with the same answers doesn't require repeating the key derivations.
+// Entries are keyed by fragment ID and an HMAC of the fragment's KDF, its
+// parameters, its salt, and the answer, keyed with a random secret which never
+// leaves the process, so neither plaintext answers nor anything which could be
+// used to check guesses at them more cheaply than the KDF are stored. Caches
+// must be safe for concurrent use.
+type KDFCache interface {
+	// Get returns the cached key for the given fragment ID and answer hash.
+	Get(fragmentID int, answerHash [32]byte) ([]byte, bool)
+
+	// Set caches the key for the given fragment ID and answer hash.
+	Set(fragmentID int, answerHash [32]byte, derivedKey []byte)
+}
+
+// NewMemKDFCache returns an in-memory KDFCache which holds up to maxEntries
+// keys, evicting and zeroing the least recently used key when full. The cache
+// is also a Destroyer, whose Destroy method zeroes and evicts every key.
+func NewMemKDFCache(maxEntries int) KDFCache {
+	return &memKDFCache{
+		hashKey: newHashKey(),
+		max:     maxEntries,
+		order:   list.New(),
+		entries: make(map[kdfCacheKey]*list.Element),
+	}
+}
+
+type kdfCacheKey struct {
+	id   int
+	hash [32]byte
+}
+
+type kdfCacheEntry struct {
+	key   kdfCacheKey
+	value []byte
+}
+
+type memKDFCache struct {
+	hashKey []byte
+	m       sync.Mutex
+	max     int
+	order   *list.List // most recently used first
+	entries map[kdfCacheKey]*list.Element
+}
+
+func (c *memKDFCache) Get(fragmentID int, answerHash [32]byte) ([]byte, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e, ok := c.entries[kdfCacheKey{fragmentID, answerHash}]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(e)
+	return cloneBytes(e.Value.(*kdfCacheEntry).value), true
+}
+
+func (c *memKDFCache) Set(fragmentID int, answerHash [32]byte, derivedKey []byte) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	k := kdfCacheKey{fragmentID, answerHash}
+	if e, ok := c.entries[k]; ok {
+		c.order.MoveToFront(e)
+		entry := e.Value.(*kdfCacheEntry)
+		zero(entry.value)
+		entry.value = cloneBytes(derivedKey)
+		return
+	}
+
+	c.entries[k] = c.order.PushFront(&kdfCacheEntry{k, cloneBytes(derivedKey)})
+
+	for c.order.Len() > c.max {
+		e := c.order.Back()
+		entry := e.Value.(*kdfCacheEntry)
+		zero(entry.value)
+		delete(c.entries, entry.key)
+		c.order.Remove(e)
+	}
+}
+
+func (c *memKDFCache) Destroy() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	for k, e := range c.entries {
+		zero(e.Value.(*kdfCacheEntry).value)
+		delete(c.entries, k)
+	}
+	c.order.Init()
+}
+
+func (c *memKDFCache) answerHashKey() []byte {
+	return c.hashKey
+}
+
+// processHashKey is the answer hash key of caches other than those made by
+// NewMemKDFCache, which hold their own.
+var processHashKey = newHashKey()
+
+// newHashKey returns a new random answer hash key.
+func newHashKey() []byte {
+	k := make([]byte, 32)
+	if _, err := rand.Read(k); err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// cacheHashKey returns the random secret used to key the cache's answer
+// hashes, which is the same for every use of the cache in the process.
+func cacheHashKey(cache KDFCache) []byte {
+	if c, ok := cache.(interface{ answerHashKey() []byte }); ok {
+		return c.answerHashKey()
+	}
+	return processHashKey
+}
+
+// answerHash returns the HMAC-SHA-256 of the fragment's KDF, its parameters,
+// its salt, and the answer, keyed with the given secret.
+func answerHash(key []byte, f Fragment, answer string) [32]byte {
+	alg, _ := parseKDF(f.KDF)
+	params, _ := Fragment{
+		Version:       FragmentVersion2,
+		KDF:           alg,
+		N:             f.N,
+		R:             f.R,
+		P:             f.P,
+		BcryptCost:    f.BcryptCost,
+		Argon2Time:    f.Argon2Time,
+		Argon2Memory:  f.Argon2Memory,
+		Argon2Threads: f.Argon2Threads,
+		KDFParams:     f.KDFParams,
+		Salt:          f.Salt,
+	}.MarshalBinary()
+
+	var h [32]byte
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(params)
+	_, _ = mac.Write([]byte(answer))
+	copy(h[:], mac.Sum(nil))
+	return h
+}
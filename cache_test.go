@@ -0,0 +1,207 @@
+package horcrux
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRecoverWithKDFCache(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	derivations := countDerivations(t)
+
+	answers := []Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	cache := NewMemKDFCache(10)
+	for i := 0; i < 3; i++ {
+		actual, err := Recover(answers, WithKDFCache(cache))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(actual, secret) {
+			t.Fatalf("Expected %v but was %v", secret, actual)
+		}
+	}
+
+	if n := atomic.LoadInt32(derivations); n != 2 {
+		t.Fatalf("Expected %v but was %v", 2, n)
+	}
+}
+
+func TestKDFCacheKeyedOnAnswerHMAC(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := frags[0].WithAnswer(questions[frags[0].Question])
+
+	cache := &recordingKDFCache{KDFCache: NewMemKDFCache(10)}
+	if _, err := DecryptShares([]Answer{a}, WithKDFCache(cache)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cache.hashes) != 1 {
+		t.Fatalf("Expected 1 entry but was %d", len(cache.hashes))
+	}
+
+	expected := answerHash(cacheHashKey(cache), a.Fragment, FoldNormalizer(a.Answer))
+	if cache.hashes[0] != expected {
+		t.Fatalf("Expected %x but was %x", expected, cache.hashes[0])
+	}
+
+	if bytes.Contains(cache.hashes[0][:], []byte(a.Answer)) {
+		t.Fatal("Expected the answer not to be stored")
+	}
+}
+
+func TestAnswerHashKeyedPerCache(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	a, b := NewMemKDFCache(10), NewMemKDFCache(10)
+
+	h := answerHash(cacheHashKey(a), f, "answer")
+	if actual := answerHash(cacheHashKey(a), f, "answer"); actual != h {
+		t.Fatalf("Expected %x but was %x", h, actual)
+	}
+
+	if answerHash(cacheHashKey(b), f, "answer") == h {
+		t.Fatal("Expected different caches to hash answers differently")
+	}
+
+	salted := f
+	salted.Salt = []byte("not the salt")
+	if answerHash(cacheHashKey(a), salted, "answer") == h {
+		t.Fatal("Expected the hash to depend on the salt")
+	}
+
+	cheaper := f
+	cheaper.N = 2
+	if answerHash(cacheHashKey(a), cheaper, "answer") == h {
+		t.Fatal("Expected the hash to depend on the KDF parameters")
+	}
+
+	// caches not made by NewMemKDFCache share the process's key
+	other := &recordingKDFCache{KDFCache: a}
+	if !bytes.Equal(cacheHashKey(other), cacheHashKey(other)) {
+		t.Fatal("Expected the same key for the same cache")
+	}
+
+	if bytes.Equal(cacheHashKey(other), cacheHashKey(a)) {
+		t.Fatal("Expected a different key for a different cache")
+	}
+}
+
+func TestMemKDFCacheEviction(t *testing.T) {
+	cache := NewMemKDFCache(2)
+	cache.Set(1, [32]byte{1}, []byte("one"))
+	cache.Set(2, [32]byte{2}, []byte("two"))
+
+	// use 1, so that 2 is least recently used
+	if _, ok := cache.Get(1, [32]byte{1}); !ok {
+		t.Fatal("Expected entry 1 to be cached")
+	}
+
+	cache.Set(3, [32]byte{3}, []byte("three"))
+
+	if _, ok := cache.Get(2, [32]byte{2}); ok {
+		t.Fatal("Expected entry 2 to be evicted")
+	}
+
+	for i, expected := range []string{"", "one", "", "three"} {
+		if expected == "" {
+			continue
+		}
+
+		actual, ok := cache.Get(i, [32]byte{byte(i)})
+		if !ok || string(actual) != expected {
+			t.Fatalf("Expected %v but was %v", expected, string(actual))
+		}
+	}
+}
+
+type recordingKDFCache struct {
+	KDFCache
+	hashes [][32]byte
+}
+
+func (c *recordingKDFCache) Set(id int, hash [32]byte, key []byte) {
+	c.hashes = append(c.hashes, hash)
+	c.KDFCache.Set(id, hash, key)
+}
+
+// sharingKDFCache returns its own copies of keys from Get.
+type sharingKDFCache struct {
+	m    sync.Mutex
+	keys map[kdfCacheKey][]byte
+}
+
+func (c *sharingKDFCache) Get(id int, hash [32]byte) ([]byte, bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	k, ok := c.keys[kdfCacheKey{id, hash}]
+	return k, ok
+}
+
+func (c *sharingKDFCache) Set(id int, hash [32]byte, key []byte) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.keys[kdfCacheKey{id, hash}] = cloneBytes(key)
+}
+
+func TestRecoverWithSharingKDFCache(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	cache := &sharingKDFCache{keys: make(map[kdfCacheKey][]byte)}
+	for i := 0; i < 3; i++ {
+		actual, err := Recover(answers, WithKDFCache(cache))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(actual, secret) {
+			t.Fatalf("Expected %v but was %v", secret, actual)
+		}
+	}
+}
+
+func TestMemKDFCacheDestroy(t *testing.T) {
+	cache := NewMemKDFCache(2)
+	cache.Set(1, [32]byte{1}, []byte("one"))
+
+	entry := cache.(*memKDFCache).entries[kdfCacheKey{1, [32]byte{1}}]
+	v := entry.Value.(*kdfCacheEntry).value
+
+	cache.(Destroyer).Destroy()
+
+	if _, ok := cache.Get(1, [32]byte{1}); ok {
+		t.Fatal("Expected entry 1 to be evicted")
+	}
+
+	if !bytes.Equal(v, make([]byte, len(v))) {
+		t.Fatalf("Expected %x to be zeroed", v)
+	}
+}
@@ -0,0 +1,54 @@
+package horcrux
+
+import "github.com/fxamacker/cbor/v2"
+
+// cborFragment has a Fragment's fields but not its methods, so that it is
+// encoded as a CBOR map of its fields rather than as a byte string holding its
+// binary encoding.
+type cborFragment Fragment
+
+// marshalCBOR returns the canonical CBOR encoding of v.
+func marshalCBOR(v interface{}) ([]byte, error) {
+	em, err := cbor.CanonicalEncOptions().EncMode()
+	if err != nil {
+		return nil, err
+	}
+	return em.Marshal(v)
+}
+
+// ToCBOR returns the fragment's canonical CBOR encoding: a map from field names
+// to values, with byte slices encoded as byte strings.
+func (f Fragment) ToCBOR() ([]byte, error) {
+	return marshalCBOR(cborFragment(f))
+}
+
+// FragmentFromCBOR decodes a fragment from its CBOR encoding.
+func FragmentFromCBOR(data []byte) (Fragment, error) {
+	var f cborFragment
+	err := cbor.Unmarshal(data, &f)
+	return Fragment(f), err
+}
+
+// ToCBOR returns the fragment set's canonical CBOR encoding: an array of its
+// fragments' encodings.
+func (fs FragmentSet) ToCBOR() ([]byte, error) {
+	v := make([]cborFragment, len(fs))
+	for i, f := range fs {
+		v[i] = cborFragment(f)
+	}
+	return marshalCBOR(v)
+}
+
+// FragmentSetFromCBOR decodes a fragment set from its CBOR encoding.
+func FragmentSetFromCBOR(data []byte) (FragmentSet, error) {
+	var v []cborFragment
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	fs := make(FragmentSet, len(v))
+	for i, f := range v {
+		fs[i] = Fragment(f)
+	}
+	return fs, nil
+}
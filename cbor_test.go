@@ -0,0 +1,99 @@
+package horcrux
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFragmentCBOR(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.Metadata = map[string]string{"owner": "alice", "a": "b", "purpose": "backup"}
+
+	b, err := f.ToCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a map, not a byte string holding the binary encoding
+	if b[0]>>5 != 5 {
+		t.Fatalf("Expected a CBOR map but was major type %d", b[0]>>5)
+	}
+
+	actual, err := FragmentFromCBOR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, f) {
+		t.Fatalf("Expected %v but was %v", f, actual)
+	}
+
+	j, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b) >= len(j) {
+		t.Fatalf("Expected CBOR (%d bytes) to be smaller than JSON (%d bytes)",
+			len(b), len(j))
+	}
+}
+
+func TestFragmentCBORCanonical(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.Metadata = make(map[string]string)
+	for _, k := range []string{"z", "yy", "x", "www", "v"} {
+		f.Metadata[k] = k
+	}
+
+	expected, err := f.ToCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		actual, err := f.Clone().ToCBOR()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(actual, expected) {
+			t.Fatalf("Expected %x but was %x", expected, actual)
+		}
+	}
+}
+
+func TestFragmentSetCBOR(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := FragmentSet(frags)
+
+	b, err := fs.ToCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := FragmentSetFromCBOR(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, fs) {
+		t.Fatalf("Expected %v but was %v", fs, actual)
+	}
+}
@@ -0,0 +1,93 @@
+package horcrux
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// structureChecksumSize is the size of a fragment's structure checksum.
+const structureChecksumSize = 8
+
+// ComputeChecksum returns the first four bytes of the SHA-256 hash of the
+// fragment's nonce, salt, and encrypted share.
+func (f Fragment) ComputeChecksum() [4]byte {
+	h := sha256.New()
+	_, _ = h.Write(f.Nonce)
+	_, _ = h.Write(f.Salt)
+	_, _ = h.Write(f.Value)
+
+	var c [4]byte
+	copy(c[:], h.Sum(nil))
+	return c
+}
+
+// ValidateChecksum returns true if the fragment's checksum matches its nonce,
+// salt, and encrypted share. This is a cheap check for accidental corruption,
+// not authentication. Recover checks the checksums of fragments which have one
+// before deriving any keys.
+func (f Fragment) ValidateChecksum() bool {
+	return f.Checksum == f.ComputeChecksum()
+}
+
+// ComputeStructureChecksum returns the first eight bytes of the SHA-256 hash of
+// the binary encoding of every fragment field used to recover its share: its
+// version, ID, set ID, weight, group, group threshold, whether it is required,
+// threshold, KDF and its parameters, cipher, question (unless it is private),
+// whether the parameters are bound, normalizer, locale, answer separator,
+// nonce, salt, encrypted share, checksum, and alternates. Fields which may
+// legitimately change after splitting, such as the answer hint, metadata,
+// attempt count, and HMAC, are not covered.
+func (f Fragment) ComputeStructureChecksum() []byte {
+	s := Fragment{
+		Version:          f.Version,
+		ID:               f.ID,
+		SetID:            f.SetID,
+		Weight:           f.Weight,
+		Group:            f.Group,
+		GroupThreshold:   f.GroupThreshold,
+		Required:         f.Required,
+		K:                f.K,
+		N:                f.N,
+		R:                f.R,
+		P:                f.P,
+		KDF:              f.KDF,
+		BcryptCost:       f.BcryptCost,
+		Argon2Time:       f.Argon2Time,
+		Argon2Memory:     f.Argon2Memory,
+		Argon2Threads:    f.Argon2Threads,
+		KDFParams:        f.KDFParams,
+		Cipher:           f.Cipher,
+		Question:         f.Question,
+		QuestionIsHashed: f.QuestionIsHashed,
+		BoundParams:      f.BoundParams,
+		NormalizerID:     f.NormalizerID,
+		Locale:           f.Locale,
+		AnswerSeparator:  f.AnswerSeparator,
+		Nonce:            f.Nonce,
+		Salt:             f.Salt,
+		Value:            f.Value,
+		Checksum:         f.Checksum,
+		Alternates:       f.Alternates,
+	}
+
+	// private questions are replaced with the plaintext question to recover
+	if f.QuestionIsHashed {
+		s.Question = ""
+	}
+
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+
+	h := sha256.Sum256(b)
+	return h[:structureChecksumSize]
+}
+
+// ValidateStructureChecksum returns true if the fragment's structure checksum
+// matches its fields. Like ValidateChecksum, this detects accidental corruption
+// or truncation, so that Recover can report a damaged fragment as such rather
+// than as a wrong answer. It is not authentication.
+func (f Fragment) ValidateStructureChecksum() bool {
+	return bytes.Equal(f.StructureChecksum, f.ComputeStructureChecksum())
+}
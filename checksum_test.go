@@ -0,0 +1,156 @@
+package horcrux
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestValidateChecksum(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if !f.ValidateChecksum() {
+			t.Fatalf("Expected fragment %d to have a valid checksum", f.ID)
+		}
+	}
+}
+
+func TestValidateChecksumBitFlips(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []func(*Fragment) []byte{
+		func(f *Fragment) []byte { return f.Nonce },
+		func(f *Fragment) []byte { return f.Salt },
+		func(f *Fragment) []byte { return f.Value },
+	} {
+		f := frags[0].Clone()
+		b := field(&f)
+		for i := range b {
+			for bit := 0; bit < 8; bit++ {
+				b[i] ^= 1 << bit
+				if f.ValidateChecksum() {
+					t.Fatalf("Expected flipping bit %d of byte %d to fail", bit, i)
+				}
+				b[i] ^= 1 << bit
+			}
+		}
+	}
+}
+
+func TestRecoverBadChecksum(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	derived := make(map[byte]bool)
+	old := derive
+	defer func() { derive = old }()
+	derive = func(f Fragment, answer string) ([]byte, error) {
+		mu.Lock()
+		derived[f.ID] = true
+		mu.Unlock()
+		return old(f, answer)
+	}
+
+	f := frags[0].Clone()
+	f.Value[0] ^= 1
+
+	answers := []Answer{
+		f.WithAnswer(questions[f.Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	_, err = Recover(answers)
+	if err == nil || !strings.Contains(err.Error(), "failed checksum") {
+		t.Fatalf("Expected checksum error but was %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if derived[f.ID] {
+		t.Fatal("Expected no key derivation but one was attempted")
+	}
+}
+
+func TestValidateStructureChecksum(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	if !f.ValidateStructureChecksum() {
+		t.Fatal("Expected a valid structure checksum")
+	}
+
+	// fields which may change after splitting aren't covered
+	f.AnswerHint = "woof"
+	f.Metadata = map[string]string{"owner": "alice"}
+	f = IncrementAttempt(f)
+	if !f.ValidateStructureChecksum() {
+		t.Fatal("Expected a valid structure checksum")
+	}
+
+	f.K = 3
+	if f.ValidateStructureChecksum() {
+		t.Fatal("Expected an invalid structure checksum")
+	}
+}
+
+func TestRecoverBadStructureChecksum(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a corrupt scrypt parameter would otherwise look like a wrong answer
+	f := frags[0]
+	f.N = 2 << 9
+
+	_, err = Recover([]Answer{
+		f.WithAnswer(questions[f.Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	})
+
+	expected := ErrCorruptFragment{ID: int(f.ID), Reason: "failed structure checksum"}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestStructureChecksumSurvivesTruncation(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := frags[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// find the longest prefix which still decodes
+	var f Fragment
+	for n := len(b) - 1; n > 0; n-- {
+		if f.UnmarshalBinary(b[:n]) == nil {
+			break
+		}
+	}
+
+	if f.StructureChecksum == nil {
+		t.Fatal("Expected the structure checksum to survive truncation")
+	}
+
+	if f.ValidateStructureChecksum() {
+		t.Fatal("Expected an invalid structure checksum")
+	}
+}
@@ -0,0 +1,225 @@
+// Command horcrux splits a secret into fragments, each encrypted with the
+// answer to a security question, and recovers it from enough answered
+// fragments.
+//
+// Usage:
+//
+//	horcrux split -k 2 -out dir/ [-questions questions.json] secret.txt
+//	horcrux recover [-out secret.txt] dir/fragment-1.pem dir/fragment-2.pem
+//
+// split writes one PEM fragment file per question to the output directory. The
+// questions and their answers are read from a JSON object mapping each question
+// to its answer if -questions is given, and prompted for otherwise. recover
+// prompts for the answers to the fragments' questions and writes the secret to
+// stdout, or to the file given with -out. Each answer is checked as it is
+// given, and wrong answers can be tried again or skipped.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codahale/horcrux"
+	"golang.org/x/term"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+const usage = `usage:
+  horcrux split -k K -out DIR [-questions FILE] SECRET
+  horcrux recover [-out FILE] FRAGMENT...`
+
+var errUsage = errors.New(usage)
+
+func run(args []string, stdin *os.File, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "split":
+		return split(args[1:], stdin, stderr)
+	case "recover":
+		return recoverSecret(args[1:], stdin, stdout, stderr)
+	}
+	return errUsage
+}
+
+func split(args []string, stdin *os.File, stderr io.Writer) error {
+	fs := flag.NewFlagSet("split", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	k := fs.Int("k", 2, "the number of fragments needed to recover the secret")
+	out := fs.String("out", ".", "the directory to write fragments to")
+	qfile := fs.String("questions", "", "a JSON file of questions and answers")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	secret, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var questions map[string]string
+	if *qfile != "" {
+		questions, err = readQuestions(*qfile)
+	} else {
+		questions, err = promptQuestions(stdin, stderr)
+	}
+	if err != nil {
+		return err
+	}
+
+	frags, err := horcrux.SplitWithOptions(secret, questions, *k)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*out, 0700); err != nil {
+		return err
+	}
+
+	for _, f := range frags {
+		path := filepath.Join(*out, fmt.Sprintf("fragment-%d.pem", f.ID))
+		if err := writeFragment(path, f); err != nil {
+			return err
+		}
+		fmt.Fprintln(stderr, "wrote", path)
+	}
+	return nil
+}
+
+func recoverSecret(args []string, stdin *os.File, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("recover", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("out", "", "the file to write the secret to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+
+	frags := make([]horcrux.Fragment, fs.NArg())
+	for i, path := range fs.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		frags[i], err = horcrux.UnmarshalFragmentPEM(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	secret, err := horcrux.RecoverInteractive(frags, stdin)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		return os.WriteFile(*out, secret, 0600)
+	}
+	_, err = stdout.Write(secret)
+	return err
+}
+
+// readQuestions reads a JSON object mapping questions to answers.
+func readQuestions(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var questions map[string]string
+	if err := json.Unmarshal(data, &questions); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return questions, nil
+}
+
+// promptQuestions prompts for questions and their answers until an empty
+// question is entered. Answers are not echoed if in is a terminal, and must be
+// entered twice.
+func promptQuestions(in *os.File, w io.Writer) (map[string]string, error) {
+	br := bufio.NewReader(in)
+	readLine := func() (string, error) {
+		s, err := br.ReadString('\n')
+		if err == io.EOF && s != "" {
+			err = nil
+		}
+		return strings.TrimSpace(s), err
+	}
+
+	readAnswer := readLine
+	if fd := int(in.Fd()); term.IsTerminal(fd) {
+		readAnswer = func() (string, error) {
+			b, err := term.ReadPassword(fd)
+			fmt.Fprintln(w)
+			return strings.TrimSpace(string(b)), err
+		}
+	}
+
+	questions := make(map[string]string)
+	for {
+		fmt.Fprint(w, "Question (leave empty to finish): ")
+		q, err := readLine()
+		if err == io.EOF || (err == nil && q == "") {
+			return questions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprint(w, "Answer: ")
+		a, err := readAnswer()
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprint(w, "Answer again: ")
+		again, err := readAnswer()
+		if err != nil {
+			return nil, err
+		}
+
+		if a != again {
+			fmt.Fprintln(w, "The answers don't match. Try again.")
+			continue
+		}
+		questions[q] = a
+	}
+}
+
+// writeFragment writes the fragment to a new PEM file, which must not already
+// exist.
+func writeFragment(path string, f horcrux.Fragment) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err := f.WritePEM(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
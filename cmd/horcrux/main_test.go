@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/codahale/horcrux"
+)
+
+var questions = map[string]string{
+	"What's your first pet's name?":     "Spot",
+	"What's your least favorite food?":  "broccoli",
+	"What's your mother's maiden name?": "Hernandez",
+}
+
+func TestMain(m *testing.M) {
+	p := horcrux.ScryptParams{N: 2 << 10, R: 8, P: 1, KeyLen: 32}
+	if err := horcrux.SetDefaultScryptParams(p); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// input returns a file containing the given lines, to stand in for stdin.
+func input(t *testing.T, lines ...string) *os.File {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func TestSplitRecover(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret.txt")
+	if err := os.WriteFile(secret, []byte("my favorite password"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	qfile := filepath.Join(dir, "questions.json")
+	j, err := json.Marshal(questions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(qfile, j, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "fragments")
+	var stderr bytes.Buffer
+	err = run([]string{"split", "-k", "2", "-out", out, "-questions", qfile, secret},
+		input(t), &bytes.Buffer{}, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(out, "fragment-*.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != len(questions) {
+		t.Fatalf("Expected %d fragments but was %v", len(questions), files)
+	}
+
+	// answer the questions in the order they're asked
+	var answers []string
+	for _, path := range files[:2] {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := horcrux.UnmarshalFragmentPEM(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		answers = append(answers, questions[f.Question])
+	}
+
+	var stdout bytes.Buffer
+	err = run(append([]string{"recover"}, files[:2]...),
+		input(t, answers...), &stdout, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stdout.String() != "my favorite password" {
+		t.Fatalf("Expected %q but was %q", "my favorite password", stdout.String())
+	}
+}
+
+func TestPromptQuestions(t *testing.T) {
+	in := input(t,
+		"Q1", "A1", "A1",
+		"Q2", "A2", "typo",
+		"Q2", "A2", "A2",
+		"")
+
+	var w bytes.Buffer
+	actual, err := promptQuestions(in, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"Q1": "A1", "Q2": "A2"}
+	if len(actual) != len(expected) || actual["Q1"] != "A1" || actual["Q2"] != "A2" {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+
+	if !strings.Contains(w.String(), "don't match") {
+		t.Fatalf("Expected a mismatch warning but was %q", w.String())
+	}
+}
+
+func TestUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"frobnicate"}, {"split"}, {"recover"}} {
+		err := run(args, input(t), &bytes.Buffer{}, &bytes.Buffer{})
+		if err != errUsage {
+			t.Errorf("Expected usage for %v but was %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package horcrux
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+)
+
+// Share commitments are blinded SHA-256 hash commitments to each share,
+// published at split time. Checking a share against its commitment detects a
+// share which has been modified since the split, but unlike Feldman's
+// verifiable secret sharing, it doesn't prove that the shares lie on a single
+// polynomial, so a dealer who committed to inconsistent shares goes unnoticed.
+// Feldman commitments need a group where discrete logarithms are hard, which
+// the sss package's bytewise GF(2^8) shares don't have. The commitments are
+// only as trustworthy as the channel they were published over.
+
+const blindLen = 32
+
+// VerifyShareCommitment returns true if the given share is the one committed
+// to at split time.
+func VerifyShareCommitment(shareID int, shareData []byte, commitments [][]byte) bool {
+	if shareID < 1 || shareID > len(commitments) {
+		return false
+	}
+
+	c := commitments[shareID-1]
+	if len(c) != blindLen+sha256.Size {
+		return false
+	}
+
+	return hmac.Equal(c[blindLen:], commitmentHash(byte(shareID), c[:blindLen], shareData))
+}
+
+// commit returns a blinded commitment to the given share. The random blinding
+// value keeps the commitment from revealing short shares.
+func commit(id byte, share []byte) ([]byte, error) {
+	blind := make([]byte, blindLen)
+	if _, err := io.ReadFull(rand.Reader, blind); err != nil {
+		return nil, err
+	}
+	return append(blind, commitmentHash(id, blind, share)...), nil
+}
+
+func commitmentHash(id byte, blind, share []byte) []byte {
+	h := sha256.New()
+	h.Write(blind)
+	h.Write([]byte{id})
+	h.Write(share)
+	return h.Sum(nil)
+}
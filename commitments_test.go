@@ -0,0 +1,137 @@
+package horcrux
+
+import (
+	"testing"
+
+	"github.com/codahale/chacha20poly1305"
+)
+
+func decryptShare(t *testing.T, f Fragment, answer string) []byte {
+	if normalize, ok := LookupNormalizer(f.NormalizerID); ok {
+		answer = normalize(answer)
+	}
+
+	k, err := deriveKey(f, answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead, err := chacha20poly1305.New(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ad, err := f.associatedData(f.Question)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := aead.Open(nil, f.Nonce, f.Value, ad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestVerifyShareCommitment(t *testing.T) {
+	res, err := SplitWithResult(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1), WithShareCommitments())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Commitments) != len(questions) {
+		t.Fatalf("Expected %d commitments but was %d",
+			len(questions), len(res.Commitments))
+	}
+
+	for _, f := range res.Fragments {
+		share := decryptShare(t, f, questions[f.Question])
+		if !VerifyShareCommitment(int(f.ID), share, res.Commitments) {
+			t.Fatalf("Share %d failed verification", f.ID)
+		}
+
+		share[0] ^= 1
+		if VerifyShareCommitment(int(f.ID), share, res.Commitments) {
+			t.Fatalf("Modified share %d passed verification", f.ID)
+		}
+	}
+}
+
+func TestVerifyShareBadID(t *testing.T) {
+	if VerifyShareCommitment(0, []byte{1}, [][]byte{make([]byte, 64)}) {
+		t.Fatal("Share with an ID of 0 passed verification")
+	}
+
+	if VerifyShareCommitment(2, []byte{1}, [][]byte{make([]byte, 64)}) {
+		t.Fatal("Share without a commitment passed verification")
+	}
+}
+
+func TestSplitWithoutCommitments(t *testing.T) {
+	res, err := SplitWithResult(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Commitments != nil {
+		t.Fatalf("Expected no commitments but was %v", res.Commitments)
+	}
+}
+
+func TestRecoverVerifyShareCommitments(t *testing.T) {
+	res, err := SplitWithResult(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1), WithShareCommitments())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = Answer{
+			Fragment: res.Fragments[i],
+			Answer:   questions[res.Fragments[i].Question],
+		}
+	}
+
+	s, err := Recover(answers, WithVerifyShareCommitments(res.Commitments))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestRecoverVerifyShareCommitmentsMismatch(t *testing.T) {
+	res, err := SplitWithResult(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1), WithShareCommitments())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := SplitWithResult(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1), WithShareCommitments())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = Answer{
+			Fragment: res.Fragments[i],
+			Answer:   questions[res.Fragments[i].Question],
+		}
+	}
+
+	s, err := Recover(answers, WithVerifyShareCommitments(other.Commitments))
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
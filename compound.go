@@ -0,0 +1,28 @@
+package horcrux
+
+import "strings"
+
+// DefaultCompoundAnswerSeparator joins the parts of compound answers to
+// fragments which don't record a separator.
+const DefaultCompoundAnswerSeparator = "\x00"
+
+// CompoundAnswer is an answer made up of several parts, e.g. the first word of
+// a childhood address and its ZIP code, all of which are needed to derive the
+// fragment's key.
+type CompoundAnswer []string
+
+// Join returns the parts of the answer joined with the given separator, for use
+// as an answer when splitting a secret.
+func (c CompoundAnswer) Join(sep string) string {
+	return strings.Join(c, sep)
+}
+
+// NewCompoundAnswer returns an answer to the fragment's question made up of the
+// given parts, joined with the fragment's answer separator.
+func NewCompoundAnswer(frag Fragment, parts ...string) Answer {
+	sep := frag.AnswerSeparator
+	if sep == "" {
+		sep = DefaultCompoundAnswerSeparator
+	}
+	return frag.WithAnswer(CompoundAnswer(parts).Join(sep))
+}
@@ -0,0 +1,73 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+var (
+	parts = map[string]CompoundAnswer{
+		"Childhood address and ZIP code?": {"Elm", "90210"},
+		"What's your real name?":          {"Rumple", "stiltskin"},
+	}
+	compoundQuestions = map[string]string{
+		"Childhood address and ZIP code?": parts["Childhood address and ZIP code?"].Join("|"),
+		"What's your real name?":          parts["What's your real name?"].Join("|"),
+	}
+)
+
+func TestCompoundAnswer(t *testing.T) {
+	frags, err := Split(secret, compoundQuestions, 2, 2<<10, 8, 1,
+		WithCompoundAnswerSeparator("|"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = NewCompoundAnswer(f, parts[f.Question]...)
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestCompoundAnswerOnePart(t *testing.T) {
+	frags, err := Split(secret, compoundQuestions, 2, 2<<10, 8, 1,
+		WithCompoundAnswerSeparator("|"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = NewCompoundAnswer(f, "Elm")
+	}
+
+	if _, err := Recover(answers); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestCompoundAnswerDifferentSeparator(t *testing.T) {
+	frags, err := Split(secret, compoundQuestions, 2, 2<<10, 8, 1,
+		WithCompoundAnswerSeparator("/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = NewCompoundAnswer(f, parts[f.Question]...)
+	}
+
+	if _, err := Recover(answers); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
@@ -0,0 +1,29 @@
+package horcrux
+
+const (
+	// Overhead is the number of bytes ChaCha20Poly1305 adds to each share, so a
+	// fragment's Value is always this much longer than the secret.
+	Overhead = 16
+
+	// NonceSize is the length, in bytes, of a fragment's Nonce.
+	NonceSize = 8
+
+	// GCMOverhead is the number of bytes AES-GCM adds to an escrowed answer.
+	GCMOverhead = 16
+
+	// GCMNonceSize is the length, in bytes, of the nonce which prefixes an
+	// escrowed answer. A fragment's EscrowedAnswer is therefore
+	// GCMNonceSize+GCMOverhead bytes longer than the answer.
+	GCMNonceSize = 12
+
+	// SaltSize is the length, in bytes, of a fragment's Salt.
+	SaltSize = saltLen
+
+	// MinScryptN is the smallest scrypt iteration parameter accepted.
+	MinScryptN = 2
+
+	// RecommendedScryptN is the recommended scrypt iteration parameter.
+	RecommendedScryptN = 2 << 14
+
+	saltLen = 32
+)
@@ -0,0 +1,45 @@
+package horcrux
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/codahale/chacha20"
+	"github.com/codahale/chacha20poly1305"
+)
+
+func TestGCMConstants(t *testing.T) {
+	b, err := aes.NewCipher(make([]byte, escrowKeyLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aead, err := cipher.NewGCM(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aead.Overhead() != GCMOverhead {
+		t.Errorf("Expected %v but was %v", aead.Overhead(), GCMOverhead)
+	}
+
+	if aead.NonceSize() != GCMNonceSize {
+		t.Errorf("Expected %v but was %v", aead.NonceSize(), GCMNonceSize)
+	}
+}
+
+func TestShareConstants(t *testing.T) {
+	aead, err := chacha20poly1305.New(make([]byte, chacha20.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aead.Overhead() != Overhead {
+		t.Errorf("Expected %v but was %v", aead.Overhead(), Overhead)
+	}
+
+	if aead.NonceSize() != NonceSize {
+		t.Errorf("Expected %v but was %v", aead.NonceSize(), NonceSize)
+	}
+}
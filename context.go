@@ -0,0 +1,35 @@
+package horcrux
+
+import "context"
+
+// SplitContext splits the given secret like SplitWithOptions, but stops and
+// returns an error as soon as the given context is cancelled or expires. Key
+// derivation cannot be interrupted, so a derivation which is already running
+// finishes in the background. An expired context produces ErrTimeout.
+func SplitContext(ctx context.Context, secret []byte, questions map[string]string, k int, opts ...SplitOption) ([]Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.ctx = ctx
+
+	res, err := split(secret, questions, k, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return res.Fragments, nil
+}
+
+// RecoverContext combines the given answers like Recover, but stops and returns
+// an error as soon as the given context is cancelled or expires, e.g. when a
+// server's client goes away mid-recovery. An expired context produces
+// ErrTimeout.
+func RecoverContext(ctx context.Context, answers []Answer, opts ...RecoverOption) ([]byte, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.ctx = ctx
+
+	return recoverSecret(answers, &cfg)
+}
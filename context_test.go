@@ -0,0 +1,64 @@
+package horcrux
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSplitContext(t *testing.T) {
+	frags, err := SplitContext(context.Background(), secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	s, err := RecoverContext(context.Background(), answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestSplitContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	frags, err := SplitContext(ctx, secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1))
+	if err != context.Canceled {
+		t.Fatalf("Expected %v but was %v", context.Canceled, err)
+	}
+
+	if frags != nil {
+		t.Fatalf("Expected no fragments but was %v", frags)
+	}
+}
+
+func TestRecoverContextExpired(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if _, err := RecoverContext(ctx, answers); err != ErrTimeout {
+		t.Fatalf("Expected %v but was %v", ErrTimeout, err)
+	}
+}
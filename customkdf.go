@@ -0,0 +1,83 @@
+package horcrux
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/codahale/chacha20"
+)
+
+// A KDF is a key derivation function which can be plugged into Split with
+// WithKDF. Its name and parameters are stored in each fragment, so it must be
+// registered with RegisterKDF before fragments are split or recovered with it.
+type KDF interface {
+	// Name returns the name under which the KDF is registered.
+	Name() string
+
+	// Derive derives a 256-bit key from the answer and salt. The key is
+	// zeroed once it has been used, so it must not be shared.
+	Derive(answer, salt []byte) ([]byte, error)
+
+	// MarshalParams returns the KDF's parameters in a form which can be
+	// passed back to its KDFFactory.
+	MarshalParams() ([]byte, error)
+}
+
+// A KDFFactory returns a KDF with the given marshalled parameters.
+type KDFFactory func(params []byte) (KDF, error)
+
+var (
+	kdfsMu sync.RWMutex
+	kdfs   = map[string]KDFFactory{}
+)
+
+// RegisterKDF registers the given factory under the given name, replacing any
+// factory already registered under that name. Because the name and parameters
+// are stored in fragments, a KDF must never change once fragments have been
+// split with it. Panics if the name is that of a built-in KDF or contains a
+// slash.
+func RegisterKDF(name string, fn KDFFactory) {
+	if _, ok := kdfVersions[name]; ok || name == "" || strings.Contains(name, "/") {
+		panic(fmt.Sprintf("horcrux: cannot register KDF %q", name))
+	}
+
+	kdfsMu.Lock()
+	defer kdfsMu.Unlock()
+
+	kdfs[name] = fn
+}
+
+// LookupKDF returns the KDF factory registered under the given name.
+func LookupKDF(name string) (KDFFactory, bool) {
+	kdfsMu.RLock()
+	defer kdfsMu.RUnlock()
+
+	fn, ok := kdfs[name]
+	return fn, ok
+}
+
+// customKey derives the fragment's key using the registered KDF named by alg.
+func customKey(f Fragment, alg, answer string) ([]byte, error) {
+	fn, ok := LookupKDF(alg)
+	if !ok {
+		return nil, fmt.Errorf("horcrux: unknown KDF %q", alg)
+	}
+
+	kdf, err := fn(f.KDFParams)
+	if err != nil {
+		return nil, ErrKDFError{Cause: err}
+	}
+
+	k, err := kdf.Derive([]byte(answer), f.Salt)
+	if err != nil {
+		return nil, ErrKDFError{Cause: err}
+	}
+
+	if len(k) != chacha20.KeySize {
+		zero(k)
+		return nil, ErrKDFError{Cause: fmt.Errorf(
+			"horcrux: KDF %q returned a %d-byte key", alg, len(k))}
+	}
+	return k, nil
+}
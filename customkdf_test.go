@@ -0,0 +1,151 @@
+package horcrux
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"testing"
+)
+
+// iteratedHMAC is a toy KDF which iterates HMAC-SHA256 a given number of times.
+type iteratedHMAC struct {
+	rounds byte
+}
+
+func (k iteratedHMAC) Name() string {
+	return "test-hmac"
+}
+
+func (k iteratedHMAC) Derive(answer, salt []byte) ([]byte, error) {
+	key := answer
+	for i := 0; i < int(k.rounds); i++ {
+		h := hmac.New(sha256.New, salt)
+		h.Write(key)
+		key = h.Sum(nil)
+	}
+	return key, nil
+}
+
+func (k iteratedHMAC) MarshalParams() ([]byte, error) {
+	return []byte{k.rounds}, nil
+}
+
+// shortKDF is a broken KDF which derives keys which are too short.
+type shortKDF struct{}
+
+func (shortKDF) Name() string {
+	return "test-short"
+}
+
+func (shortKDF) Derive(answer, salt []byte) ([]byte, error) {
+	return make([]byte, 16), nil
+}
+
+func (shortKDF) MarshalParams() ([]byte, error) {
+	return nil, nil
+}
+
+func init() {
+	RegisterKDF("test-short", func([]byte) (KDF, error) {
+		return shortKDF{}, nil
+	})
+
+	RegisterKDF("test-hmac", func(params []byte) (KDF, error) {
+		if len(params) != 1 || params[0] == 0 {
+			return nil, errors.New("bad params")
+		}
+		return iteratedHMAC{rounds: params[0]}, nil
+	})
+}
+
+func TestSplitRecoverCustomKDF(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0,
+		WithKDF(iteratedHMAC{rounds: 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.KDF != "test-hmac" || string(f.KDFParams) != "\x03" || f.N != 0 {
+			t.Fatalf("Expected a custom KDF fragment but was %v", f)
+		}
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		b, err := frags[i].MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var f Fragment
+		if err := f.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestRecoverMixedCustomKDF(t *testing.T) {
+	frags := splitV1(t)
+
+	f, err := UpgradeFragment(frags[0], questions[frags[0].Question],
+		WithKDF(iteratedHMAC{rounds: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Recover([]Answer{
+		f.WithAnswer(questions[f.Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestSplitUnregisteredKDF(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0,
+		WithKDF(unregisteredKDF{}))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := `horcrux: unknown KDF "unregistered"`
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+type unregisteredKDF struct {
+	iteratedHMAC
+}
+
+func (unregisteredKDF) Name() string {
+	return "unregistered"
+}
+
+func TestRegisterBuiltinKDF(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Expected panic but got none")
+		}
+	}()
+
+	RegisterKDF(KDFScrypt, nil)
+}
@@ -0,0 +1,85 @@
+package horcrux
+
+import "errors"
+
+// RecoverEarly combines the given answers like Recover, but stops deriving keys
+// as soon as K shares have been decrypted. Keys are derived concurrently for as
+// many answers as are still needed, so if every answer is correct, only K keys
+// are derived. Answers which fail to decrypt are skipped; if fewer than K
+// answers can be decrypted, the last error is returned.
+func RecoverEarly(answers []Answer, opts ...RecoverOption) ([]byte, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if len(answers) == 0 {
+		return nil, errors.New("horcrux: need at least 1 answer")
+	}
+
+	k := answers[0].K
+	h := cfg.getHooks().synchronized()
+	cfg.hooks = &h
+	h.recoverStart(len(answers), k)
+
+	if cfg.strictKDF {
+		if err := checkKDFs(answers); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkSets(answers); err != nil {
+		return nil, err
+	}
+
+	if err := checkUngrouped(answers); err != nil {
+		return nil, err
+	}
+
+	if have := answersWeight(answers); k > have {
+		return nil, ErrTooFewAnswers{Need: k, Have: have}
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	type result struct {
+		s   share
+		err error
+	}
+
+	var lastErr error
+	shares := make([]share, 0, k)
+	defer func() { wipeShares(shares) }()
+	for next := 0; sharesWeight(shares) < k; {
+		need := k - sharesWeight(shares)
+		if answersWeight(answers[next:]) < need {
+			return nil, lastErr
+		}
+
+		// a weighted fragment may hold more than one of the shares needed
+		if next+need > len(answers) {
+			need = len(answers) - next
+		}
+
+		results := make(chan result, need)
+		for _, a := range answers[next : next+need] {
+			go func(a Answer) {
+				s, err := openShare(ctx, a, &cfg)
+				results <- result{s, err}
+			}(a)
+		}
+		next += need
+
+		for i := 0; i < need; i++ {
+			r := <-results
+			if r.err != nil {
+				lastErr = r.err
+				continue
+			}
+			shares = append(shares, r.s)
+		}
+	}
+
+	return safeCombine(shares, k)
+}
@@ -0,0 +1,102 @@
+package horcrux
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func countDerivations(t *testing.T) *int32 {
+	var n int32
+	d := derive
+	derive = func(f Fragment, answer string) ([]byte, error) {
+		atomic.AddInt32(&n, 1)
+		return d(f, answer)
+	}
+	t.Cleanup(func() { derive = d })
+	return &n
+}
+
+func TestRecoverEarly(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+
+	n := countDerivations(t)
+
+	s, err := RecoverEarly(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+
+	if *n != 2 {
+		t.Fatalf("Expected 2 key derivations but was %d", *n)
+	}
+}
+
+func TestRecoverEarlySkipsBadAnswers(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+	answers[0].Answer = "wrong"
+
+	n := countDerivations(t)
+
+	s, err := RecoverEarly(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+
+	if *n != 3 {
+		t.Fatalf("Expected 3 key derivations but was %d", *n)
+	}
+}
+
+func TestRecoverEarlyTooManyBadAnswers(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = f.WithAnswer("wrong")
+	}
+	answers[0].Answer = questions[frags[0].Question]
+
+	s, err := RecoverEarly(answers)
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	var e ErrAuthenticationFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected ErrAuthenticationFailed but was %v", err)
+	}
+
+	expected := "message authentication failed"
+	actual := e.Cause.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
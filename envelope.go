@@ -0,0 +1,31 @@
+package horcrux
+
+import "bytes"
+
+// SplitEnvelope splits the secret in envelope mode: the secret is encrypted
+// under a random 256-bit data key, and only the data key is split. It returns
+// the fragments, which are the same size no matter how long the secret is, and
+// the ciphertext, which must be kept alongside them. The ciphertext is in the
+// format written by SplitReader.
+func SplitEnvelope(secret []byte, questions map[string]string, k int, opts ...SplitOption) ([]Fragment, []byte, error) {
+	var buf bytes.Buffer
+	frags, err := SplitReader(bytes.NewReader(secret), &buf, questions, k, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return frags, buf.Bytes(), nil
+}
+
+// RecoverEnvelope recovers the data key from the given answers and uses it to
+// decrypt the ciphertext returned by SplitEnvelope, returning the secret.
+func RecoverEnvelope(answers []Answer, ciphertext []byte, opts ...RecoverOption) ([]byte, error) {
+	// size the buffer up front so the secret is never copied as it grows
+	var buf bytes.Buffer
+	buf.Grow(len(ciphertext))
+
+	if _, err := RecoverReader(answers, bytes.NewReader(ciphertext), &buf, opts...); err != nil {
+		zero(buf.Bytes())
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitEnvelope(t *testing.T) {
+	var sizes []int
+	for _, secret := range [][]byte{secret, bytes.Repeat(secret, 1000)} {
+		frags, ciphertext, err := SplitEnvelope(secret, questions, 2,
+			WithScryptParams(2<<10, 8, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := frags[0].MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(b)-len(frags[0].Question))
+
+		answers := make([]Answer, 2)
+		for i := range answers {
+			answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+		}
+
+		actual, err := RecoverEnvelope(answers, ciphertext)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(actual, secret) {
+			t.Fatalf("Expected %q but was %q", secret, actual)
+		}
+	}
+
+	if sizes[0] != sizes[1] {
+		t.Fatalf("Expected fragments of %d bytes but was %d", sizes[0], sizes[1])
+	}
+}
+
+func TestRecoverEnvelopeWrongCiphertext(t *testing.T) {
+	frags, _, err := SplitEnvelope(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ciphertext, err := SplitEnvelope(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	if _, err := RecoverEnvelope(answers, ciphertext); err != ErrCorruptStream {
+		t.Fatalf("Expected %v but was %v", ErrCorruptStream, err)
+	}
+}
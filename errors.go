@@ -0,0 +1,93 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrTooFewAnswers is returned by Recover when there are fewer answers than the
+// fragments' threshold.
+type ErrTooFewAnswers struct {
+	Need int // Need is the number of answers required.
+	Have int // Have is the number of answers given.
+}
+
+func (e ErrTooFewAnswers) Error() string {
+	return fmt.Sprintf("horcrux: need at least %d answers but only have %d",
+		e.Need, e.Have)
+}
+
+// ErrWrongAnswer is returned when a fragment's share cannot be decrypted with
+// the given answer. It is the same type as ErrAuthenticationFailed.
+type ErrWrongAnswer = ErrAuthenticationFailed
+
+// ErrCorruptFragment is returned when a fragment fails an integrity check
+// before or after its share is decrypted: its checksum or HMAC doesn't match,
+// or its share doesn't match its commitment.
+type ErrCorruptFragment struct {
+	ID     int    // ID is the ID of the fragment.
+	Reason string // Reason describes the check which failed.
+}
+
+func (e ErrCorruptFragment) Error() string {
+	return fmt.Sprintf("horcrux: fragment %d %s", e.ID, e.Reason)
+}
+
+// ErrAnswersFailed is returned by Recover when one or more answers are wrong.
+// Every answer is tried, so it lists all of the wrong answers, not just the
+// first.
+type ErrAnswersFailed struct {
+	Decrypted []int            // Decrypted are the IDs of the decrypted fragments.
+	Failed    []ErrWrongAnswer // Failed are the wrong answers' errors, by ID.
+}
+
+func (e ErrAnswersFailed) Error() string {
+	failed := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		failed[i] = fmt.Sprint(f.ID)
+	}
+
+	decrypted := make([]string, len(e.Decrypted))
+	for i, id := range e.Decrypted {
+		decrypted[i] = fmt.Sprint(id)
+	}
+
+	return fmt.Sprintf("horcrux: wrong answers for fragments [%s] (decrypted [%s])",
+		strings.Join(failed, " "), strings.Join(decrypted, " "))
+}
+
+// Unwrap returns the wrong answers' errors, so that errors.As can find them.
+func (e ErrAnswersFailed) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f
+	}
+	return errs
+}
+
+// answersFailed returns an ErrAnswersFailed if any of the given errors, one per
+// answer, is a wrong answer. The answers' shares are used to find the IDs of
+// the decrypted fragments.
+func answersFailed(shares []share, wrong []error) error {
+	var e ErrAnswersFailed
+	for i, err := range wrong {
+		var auth ErrWrongAnswer
+		if errors.As(err, &auth) {
+			e.Failed = append(e.Failed, auth)
+		} else {
+			e.Decrypted = append(e.Decrypted, int(shares[i].id))
+		}
+	}
+
+	if len(e.Failed) == 0 {
+		return nil
+	}
+
+	sort.Ints(e.Decrypted)
+	sort.Slice(e.Failed, func(i, j int) bool {
+		return e.Failed[i].ID < e.Failed[j].ID
+	})
+	return e
+}
@@ -0,0 +1,99 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestErrTooFewAnswers(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Recover([]Answer{frags[0].WithAnswer(questions[frags[0].Question])})
+
+	var e ErrTooFewAnswers
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected ErrTooFewAnswers but was %v", err)
+	}
+
+	if e.Need != 2 || e.Have != 1 {
+		t.Fatalf("Expected 2 and 1 but was %d and %d", e.Need, e.Have)
+	}
+}
+
+func TestErrWrongAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Recover([]Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer("wrong"),
+	})
+
+	var e ErrWrongAnswer
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected ErrWrongAnswer but was %v", err)
+	}
+
+	if e.ID != int(frags[1].ID) {
+		t.Fatalf("Expected %v but was %v", frags[1].ID, e.ID)
+	}
+}
+
+func TestErrCorruptFragment(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frags[0].Value[0] ^= 1
+
+	_, err = Recover([]Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	})
+
+	expected := ErrCorruptFragment{ID: int(frags[0].ID), Reason: "failed checksum"}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestErrAnswersFailed(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Recover([]Answer{
+		frags[2].WithAnswer("wrong"),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+		frags[0].WithAnswer("also wrong"),
+	})
+
+	var e ErrAnswersFailed
+	if !errors.As(err, &e) {
+		t.Fatalf("Expected ErrAnswersFailed but was %v", err)
+	}
+
+	if len(e.Failed) != 2 || e.Failed[0].ID != int(frags[0].ID) ||
+		e.Failed[1].ID != int(frags[2].ID) {
+		t.Fatalf("Expected fragments %d and %d to fail but was %v",
+			frags[0].ID, frags[2].ID, e.Failed)
+	}
+
+	if !reflect.DeepEqual(e.Decrypted, []int{int(frags[1].ID)}) {
+		t.Fatalf("Expected %v but was %v", []int{int(frags[1].ID)}, e.Decrypted)
+	}
+
+	expected := fmt.Sprintf("horcrux: wrong answers for fragments [%d %d] (decrypted [%d])",
+		frags[0].ID, frags[2].ID, frags[1].ID)
+	if err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
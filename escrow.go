@@ -0,0 +1,63 @@
+package horcrux
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const escrowKeyLen = 32
+
+// RecoverEscrowedAnswer decrypts the fragment's escrowed answer with the given
+// escrow key.
+func RecoverEscrowedAnswer(f Fragment, escrowKey []byte) (string, error) {
+	aead, err := newEscrowAEAD(escrowKey)
+	if err != nil {
+		return "", err
+	}
+
+	if len(f.EscrowedAnswer) < aead.NonceSize() {
+		return "", errors.New("horcrux: fragment has no escrowed answer")
+	}
+
+	nonce := f.EscrowedAnswer[:aead.NonceSize()]
+	ciphertext := f.EscrowedAnswer[aead.NonceSize():]
+	a, err := aead.Open(nil, nonce, ciphertext, []byte(f.Question))
+	if err != nil {
+		return "", err
+	}
+	return string(a), nil
+}
+
+// escrowAnswer encrypts the answer to the given question with AES-256-GCM,
+// using the question as stored in the fragment, i.e. hashed if private, as
+// associated data, and returns the nonce followed by the ciphertext.
+func escrowAnswer(escrowKey []byte, question, answer string) ([]byte, error) {
+	aead, err := newEscrowAEAD(escrowKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, []byte(answer), []byte(question)), nil
+}
+
+func newEscrowAEAD(escrowKey []byte) (cipher.AEAD, error) {
+	if len(escrowKey) != escrowKeyLen {
+		return nil, fmt.Errorf("horcrux: escrow key must be %d bytes",
+			escrowKeyLen)
+	}
+
+	block, err := aes.NewCipher(escrowKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
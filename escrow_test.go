@@ -0,0 +1,95 @@
+package horcrux
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestAnswerEscrow(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithAnswerEscrow(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		actual, err := RecoverEscrowedAnswer(f, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := questions[f.Question]
+		if actual != expected {
+			t.Fatalf("Expected %v but was %v", expected, actual)
+		}
+	}
+}
+
+func TestAnswerEscrowPrivateQuestions(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithAnswerEscrow(key),
+		WithPrivateQuestions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make(map[string]string, len(questions))
+	for q, a := range questions {
+		answers[hex.EncodeToString(hashQuestion(q))] = a
+	}
+
+	for _, f := range frags {
+		actual, err := RecoverEscrowedAnswer(f, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := answers[f.Question]
+		if actual != expected {
+			t.Fatalf("Expected %v but was %v", expected, actual)
+		}
+	}
+}
+
+func TestAnswerEscrowWrongKey(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAnswerEscrow(bytes.Repeat([]byte{7}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := RecoverEscrowedAnswer(frags[0], bytes.Repeat([]byte{8}, 32))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", a)
+	}
+}
+
+func TestAnswerEscrowBadKey(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAnswerEscrow([]byte("short")))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := "horcrux: escrow key must be 32 bytes"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestSplitWithoutEscrow(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.EscrowedAnswer != nil {
+			t.Fatalf("Expected no escrowed answer but was %x", f.EscrowedAnswer)
+		}
+	}
+}
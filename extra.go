@@ -0,0 +1,70 @@
+package horcrux
+
+import "sort"
+
+// WithKey returns an answer which uses the given key to decrypt an extra
+// fragment. See WithExtraShares.
+func (f Fragment) WithKey(key []byte) Answer {
+	return f.WithAnswer(string(key))
+}
+
+// takeExtraShares removes the given number of shares with the highest IDs from
+// shares and returns them.
+func takeExtraShares(shares map[byte][]byte, extra int) map[byte][]byte {
+	if extra <= 0 {
+		return nil
+	}
+
+	ids := make([]byte, 0, len(shares))
+	for id := range shares {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+
+	m := make(map[byte][]byte, extra)
+	for _, id := range ids[:extra] {
+		m[id] = shares[id]
+		delete(shares, id)
+	}
+	return m
+}
+
+// encryptExtraShares encrypts the extra shares with the extra share key, adding
+// them and their commitments to the result.
+func encryptExtraShares(res *SplitResult, extra map[byte][]byte, k int, cfg *splitConfig) error {
+	ecfg := splitConfig{kdf: KDFKey, rand: cfg.rand}
+	if len(res.Fragments) > 0 {
+		ecfg.setID = res.Fragments[0].SetID
+	}
+
+	ids := make([]byte, 0, len(extra))
+	for id := range extra {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	key := string(cfg.extraShareKey)
+	for _, id := range ids {
+		var f Fragment
+		if err := encryptShare(ctx, &f, id, k, "", key, key, extra[id], &ecfg); err != nil {
+			return err
+		}
+		res.ExtraFragments = append(res.ExtraFragments, f)
+
+		if cfg.commitments {
+			c, err := commit(id, extra[id])
+			if err != nil {
+				return err
+			}
+
+			for len(res.Commitments) < int(id) {
+				res.Commitments = append(res.Commitments, nil)
+			}
+			res.Commitments[id-1] = c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitWithExtraShares(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+
+	res, err := SplitWithResult(secret, questions, 3,
+		WithScryptParams(2<<10, 8, 1),
+		WithExtraShares(2),
+		WithExtraShareKey(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.ExtraFragments) != 2 {
+		t.Fatalf("Expected %v but was %v", 2, len(res.ExtraFragments))
+	}
+
+	var all []Answer
+	for _, f := range res.Fragments {
+		all = append(all, f.WithAnswer(questions[f.Question]))
+	}
+	for _, f := range res.ExtraFragments {
+		if f.Question != "" {
+			t.Fatalf("Expected no question but was %q", f.Question)
+		}
+		all = append(all, f.WithKey(key))
+	}
+
+	// try every subset of K answers
+	for a := 0; a < len(all); a++ {
+		for b := a + 1; b < len(all); b++ {
+			for c := b + 1; c < len(all); c++ {
+				answers := []Answer{all[a], all[b], all[c]}
+
+				actual, err := Recover(answers)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !bytes.Equal(actual, secret) {
+					t.Fatalf("Expected %v but was %v", secret, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestSplitWithExtraSharesWrongKey(t *testing.T) {
+	res, err := SplitWithResult(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1),
+		WithExtraShares(1),
+		WithExtraShareKey(bytes.Repeat([]byte{7}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		res.Fragments[0].WithAnswer(questions[res.Fragments[0].Question]),
+		res.ExtraFragments[0].WithKey(bytes.Repeat([]byte{8}, 32)),
+	}
+
+	if _, err := Recover(answers); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestSplitWithExtraSharesNoKey(t *testing.T) {
+	_, err := SplitWithResult(secret, questions, 2, WithExtraShares(1))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	expected := "horcrux: extra share key must be 32 bytes"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
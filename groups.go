@@ -0,0 +1,280 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codahale/sss"
+)
+
+// A Group is a set of security questions with its own threshold, for use with
+// SplitGroups.
+type Group struct {
+	Questions map[string]string // Questions maps each question to its answer.
+	K         int               // K is the number of answers the group needs.
+}
+
+// ErrTooFewGroups is returned by Recover when there are too few groups with
+// enough answers to recover a secret split with SplitGroups.
+type ErrTooFewGroups struct {
+	Need int // Need is the number of groups required.
+	Have int // Have is the number of groups with enough answers.
+}
+
+func (e ErrTooFewGroups) Error() string {
+	return fmt.Sprintf("horcrux: need at least %d groups but only have %d",
+		e.Need, e.Have)
+}
+
+// SplitGroups splits the given secret between groups of security questions,
+// e.g. personal and institutional questions, so that recovering it needs K
+// answers from each of groupK groups, like SLIP-39 group shares. The secret is
+// split into one share per group, with a threshold of groupK, and each group's
+// share is split again between its questions, with the group's threshold.
+// Either threshold may be 1, in which case each group or question holds the
+// whole secret or group share. Every fragment records its group and the group
+// threshold, and Recover combines each group's answers before combining the
+// groups. Unless overridden with WithScryptParams, the recommended scrypt
+// parameters are used.
+func SplitGroups(secret []byte, groups []Group, groupK int, opts ...SplitOption) ([]Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.requiredQuestion != "" {
+		return nil, errors.New("horcrux: a required question can't be used with groups")
+	}
+	return splitIntoGroups(secret, groups, groupK, cfg)
+}
+
+func splitIntoGroups(secret []byte, groups []Group, groupK int, cfg splitConfig) ([]Fragment, error) {
+	if len(groups) > 255 {
+		return nil, fmt.Errorf("horcrux: too many groups (%d)", len(groups))
+	}
+
+	if groupK < 1 || groupK > len(groups) {
+		return nil, fmt.Errorf("horcrux: need %d of %d groups", groupK,
+			len(groups))
+	}
+
+	if cfg.extraShares > 0 {
+		return nil, errors.New("horcrux: extra shares can't be used with groups")
+	}
+
+	seen := make(map[string]bool)
+	questions := make([]map[string]string, len(groups))
+	for i, g := range groups {
+		qs, err := normalizeQuestions(g.Questions)
+		if err != nil {
+			return nil, err
+		}
+
+		for q := range qs {
+			if seen[q] {
+				return nil, fmt.Errorf("horcrux: duplicate question %q", q)
+			}
+			seen[q] = true
+		}
+
+		n := cfg.totalWeight(qs)
+		if n > 255 {
+			return nil, fmt.Errorf("horcrux: too many shares (%d)", n)
+		}
+
+		if g.K < 1 || g.K > n {
+			return nil, fmt.Errorf("horcrux: group %d needs %d of %d answers",
+				i+1, g.K, n)
+		}
+		questions[i] = qs
+	}
+
+	groupShares, err := splitShares(byte(len(groups)), groupK, secret)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroShares(groupShares)
+
+	// every group's fragments share a set ID
+	cfg.setID, err = newSetID(cfg.random())
+	if err != nil {
+		return nil, err
+	}
+	cfg.groupThreshold = groupK
+
+	var frags []Fragment
+	for i, g := range groups {
+		shares, err := splitShares(byte(cfg.totalWeight(questions[i])), g.K,
+			groupShares[byte(i+1)])
+		if err != nil {
+			return nil, err
+		}
+
+		gcfg := cfg
+		gcfg.group = i + 1
+		res, err := encryptShares(shares, questions[i], g.K, &gcfg)
+		zeroShares(shares)
+		if err != nil {
+			return nil, err
+		}
+		frags = append(frags, res.Fragments...)
+	}
+	return frags, nil
+}
+
+// splitShares splits the secret like sss.Split, but with a threshold of 1,
+// gives every share a copy of the secret.
+func splitShares(n byte, k int, secret []byte) (map[byte][]byte, error) {
+	if k != 1 {
+		return sss.Split(n, byte(k), secret)
+	}
+
+	shares := make(map[byte][]byte, n)
+	for id := byte(1); id <= n && id != 0; id++ {
+		shares[id] = append([]byte(nil), secret...)
+	}
+	return shares, nil
+}
+
+// zeroShares zeroes each of the given shares.
+func zeroShares(shares map[byte][]byte) {
+	for _, s := range shares {
+		zero(s)
+	}
+}
+
+// wipeShares zeroes the values of each of the given shares.
+func wipeShares(shares []share) {
+	for _, s := range shares {
+		zero(s.value)
+	}
+}
+
+// grouped returns true if the answers' fragments were split with SplitGroups.
+func grouped(answers []Answer) bool {
+	for _, a := range answers {
+		if a.Group != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkUngrouped returns an error if any of the answers' fragments were split
+// with SplitGroups, which only Recover can combine.
+func checkUngrouped(answers []Answer) error {
+	if grouped(answers) {
+		return errors.New("horcrux: fragments split into groups can only be combined by Recover")
+	}
+	return nil
+}
+
+// checkGroups returns ErrTooFewGroups if there are too few groups with enough
+// answers to recover the secret, or an error if the answers' fragments don't
+// agree on the group threshold.
+func checkGroups(answers []Answer) error {
+	need := answers[0].GroupThreshold
+	byGroup := make(map[int][]Answer)
+	for _, a := range answers {
+		if a.Group == 0 || a.GroupThreshold != need {
+			return fmt.Errorf("horcrux: fragment %d has a different group threshold",
+				a.ID)
+		}
+		byGroup[a.Group] = append(byGroup[a.Group], a)
+	}
+
+	enough := 0
+	for _, as := range byGroup {
+		if answersWeight(as) >= as[0].K {
+			enough++
+		}
+	}
+
+	if enough < need {
+		return ErrTooFewGroups{Need: need, Have: enough}
+	}
+	return nil
+}
+
+// combineGroups combines the shares decrypted from each group's answers into
+// that group's share, then combines the group shares into the secret. shares
+// must be in the same order as answers.
+func combineGroups(answers []Answer, shares []share) ([]byte, error) {
+	byGroup := make(map[int][]share)
+	k := make(map[int]int)
+	for i, a := range answers {
+		byGroup[a.Group] = append(byGroup[a.Group], shares[i])
+		k[a.Group] = a.K
+	}
+
+	var groupShares []share
+	defer func() { wipeShares(groupShares) }()
+
+	for g, gs := range byGroup {
+		if sharesWeight(gs) < k[g] {
+			continue
+		}
+
+		v, err := combineShares(gs, k[g])
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, share{id: byte(g), value: v})
+	}
+
+	need := answers[0].GroupThreshold
+	if len(groupShares) < need {
+		return nil, ErrTooFewGroups{Need: need, Have: len(groupShares)}
+	}
+	return combineShares(groupShares, need)
+}
+
+// combineShares combines the shares like safeCombine, but with a threshold of
+// 1, returns a copy of the first share, which every share is a copy of.
+func combineShares(shares []share, k int) ([]byte, error) {
+	if k != 1 {
+		return safeCombine(shares, k)
+	}
+
+	s := expandShares(shares)[0].value
+	if len(s) == 0 {
+		return nil, ErrEmptyRecoveredSecret
+	}
+	return append([]byte(nil), s...), nil
+}
+
+// errRequiredQuestion is returned when a required question is given to a
+// function which can't make it a group of its own, as Split does.
+var errRequiredQuestion = errors.New("horcrux: a required question can only be used with Split")
+
+// splitRequired splits the secret so that recovering it needs the answer to
+// the required question and k-1 of the others, by making the required question
+// a group of its own.
+func splitRequired(secret []byte, questions map[string]string, k int, cfg *splitConfig) (SplitResult, error) {
+	required, ok := questions[cfg.requiredQuestion]
+	if !ok {
+		return SplitResult{}, fmt.Errorf("horcrux: no required question %q",
+			cfg.requiredQuestion)
+	}
+
+	if k < 2 {
+		return SplitResult{}, fmt.Errorf(
+			"horcrux: need at least 2 answers with a required question, not %d", k)
+	}
+
+	others := make(map[string]string, len(questions)-1)
+	for q, a := range questions {
+		if q != cfg.requiredQuestion {
+			others[q] = a
+		}
+	}
+
+	frags, err := splitIntoGroups(secret, []Group{
+		{Questions: map[string]string{cfg.requiredQuestion: required}, K: 1},
+		{Questions: others, K: k - 1},
+	}, 2, *cfg)
+	if err != nil {
+		return SplitResult{}, err
+	}
+	return SplitResult{Fragments: frags}, nil
+}
@@ -0,0 +1,285 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+var (
+	personal = map[string]string{
+		"What's your first pet's name?":     "Spot",
+		"What's your least favorite food?":  "broccoli",
+		"What's your mother's maiden name?": "Hernandez",
+	}
+	institutional = map[string]string{
+		"What's your bank's branch number?": "1234",
+		"What's your employee number?":      "5678",
+	}
+)
+
+func splitGroups(t *testing.T) map[string]Answer {
+	frags, err := SplitGroups(secret, []Group{
+		{Questions: personal, K: 2},
+		{Questions: institutional, K: 1},
+	}, 2, WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make(map[string]Answer, len(frags))
+	for _, f := range frags {
+		a, ok := personal[f.Question]
+		if !ok {
+			a = institutional[f.Question]
+		}
+		answers[f.Question] = f.WithAnswer(a)
+	}
+	return answers
+}
+
+func TestSplitGroups(t *testing.T) {
+	answers := splitGroups(t)
+
+	if len(answers) != len(personal)+len(institutional) {
+		t.Fatalf("Expected %v but was %v", len(personal)+len(institutional),
+			len(answers))
+	}
+
+	for q, a := range answers {
+		expected := 1
+		if _, ok := institutional[q]; ok {
+			expected = 2
+		}
+
+		if a.Group != expected || a.GroupThreshold != 2 {
+			t.Fatalf("Expected %v/%v but was %v/%v", expected, 2, a.Group,
+				a.GroupThreshold)
+		}
+	}
+
+	s, err := Recover([]Answer{
+		answers["What's your first pet's name?"],
+		answers["What's your mother's maiden name?"],
+		answers["What's your employee number?"],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+}
+
+func TestRecoverTooFewGroups(t *testing.T) {
+	answers := splitGroups(t)
+
+	// every personal answer, but no institutional ones
+	var personalAnswers []Answer
+	for q := range personal {
+		personalAnswers = append(personalAnswers, answers[q])
+	}
+
+	_, err := Recover(personalAnswers)
+
+	expected := ErrTooFewGroups{Need: 2, Have: 1}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+
+	// both groups, but too few personal answers
+	_, err = Recover([]Answer{
+		answers["What's your first pet's name?"],
+		answers["What's your bank's branch number?"],
+		answers["What's your employee number?"],
+	})
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestIsReadyForRecoveryGroups(t *testing.T) {
+	answers := splitGroups(t)
+
+	var frags []Fragment
+	for _, a := range answers {
+		frags = append(frags, a.Fragment)
+	}
+
+	if IsReadyForRecovery(frags, personal) {
+		t.Fatal("Expected not to be ready with only one group")
+	}
+
+	available := map[string]string{
+		"What's your first pet's name?":    "Spot",
+		"What's your least favorite food?": "broccoli",
+		"What's your employee number?":     "5678",
+	}
+	if !IsReadyForRecovery(frags, available) {
+		t.Fatal("Expected to be ready")
+	}
+}
+
+func TestSplitGroupsBadThreshold(t *testing.T) {
+	_, err := SplitGroups(secret, []Group{{Questions: personal, K: 4}}, 1)
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	_, err = SplitGroups(secret, []Group{{Questions: personal, K: 2}}, 2)
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestRecoverEarlyGroups(t *testing.T) {
+	answers := splitGroups(t)
+
+	var all []Answer
+	for _, a := range answers {
+		all = append(all, a)
+	}
+
+	if _, err := RecoverEarly(all); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestSplitWithRequiredQuestion(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1,
+		WithRequiredQuestion("What's your real name?"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var required Answer
+	var others []Answer
+	for _, a := range allAnswers(frags) {
+		if a.Required {
+			required = a
+		} else {
+			others = append(others, a)
+		}
+	}
+
+	if required.Question != "What's your real name?" || len(others) != 3 {
+		t.Fatalf("Expected one required fragment but was %v", frags)
+	}
+
+	s, err := Recover([]Answer{required, others[0], others[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+
+	_, err = Recover(others)
+
+	expected := ErrTooFewGroups{Need: 2, Have: 1}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestSplitWithRequiredQuestionBadThreshold(t *testing.T) {
+	_, err := Split(secret, questions, 1, 2<<10, 8, 1,
+		WithRequiredQuestion("What's your real name?"))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestSplitWithUnknownRequiredQuestion(t *testing.T) {
+	_, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithRequiredQuestion("What's your favorite color?"))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestRequiredQuestionOnlyWithSplit(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	answers := allAnswers(frags)
+
+	required := WithRequiredQuestion("What's your real name?")
+	fast := WithScryptParams(2<<10, 8, 1)
+
+	entryPoints := map[string]func() error{
+		"SplitPooled": func() error {
+			_, err := SplitPooled(secret, questions, 2, fast, required)
+			return err
+		},
+		"EncryptShares": func() error {
+			_, err := EncryptShares(map[int][]byte{1: {1}, 2: {2}, 3: {3}, 4: {4}},
+				questions, 2, fast, required)
+			return err
+		},
+		"ProactiveResplit": func() error {
+			_, err := ProactiveResplit(frags, answers, 3, fast, required)
+			return err
+		},
+		"AddFragment": func() error {
+			_, err := AddFragment(frags, answers[:2], "What's your favorite color?",
+				"blue", required)
+			return err
+		},
+		"RotateAnswer": func() error {
+			_, err := RotateAnswer(frags[0], answers[0].Answer, "new answer",
+				WithRequiredQuestion(frags[0].Question))
+			return err
+		},
+		"RefreshFragments": func() error {
+			_, err := RefreshFragments(answers, required)
+			return err
+		},
+	}
+
+	for name, fn := range entryPoints {
+		t.Run(name, func(t *testing.T) {
+			if err := fn(); err != errRequiredQuestion {
+				t.Fatalf("Expected %v but was %v", errRequiredQuestion, err)
+			}
+		})
+	}
+}
+
+func TestRotateAnswerKeepsRequired(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1,
+		WithRequiredQuestion("What's your real name?"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var required Answer
+	var others []Answer
+	for _, a := range allAnswers(frags) {
+		if a.Required {
+			required = a
+		} else {
+			others = append(others, a)
+		}
+	}
+
+	rotated, err := RotateAnswer(required.Fragment, required.Answer, "Bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !rotated.Required || rotated.Group != required.Group {
+		t.Fatalf("Expected a required fragment but was %v", rotated)
+	}
+
+	s, err := Recover([]Answer{rotated.WithAnswer("Bob"), others[0], others[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+}
@@ -0,0 +1,28 @@
+package horcrux
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// ComputeHMAC returns the HMAC-SHA256 of the fragment's binary encoding, minus
+// its HMAC field, using the given key. Store the result in the fragment's HMAC
+// field to protect the fragment's unencrypted fields, such as its question and
+// KDF parameters, from modification.
+func (f Fragment) ComputeHMAC(key []byte) []byte {
+	f.HMAC = nil
+	b, err := f.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+
+	h := hmac.New(sha256.New, key)
+	h.Write(b)
+	return h.Sum(nil)
+}
+
+// VerifyHMAC returns true if the fragment's HMAC field is valid for the given
+// key.
+func (f Fragment) VerifyHMAC(key []byte) bool {
+	return len(f.HMAC) == sha256.Size && hmac.Equal(f.HMAC, f.ComputeHMAC(key))
+}
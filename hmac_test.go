@@ -0,0 +1,61 @@
+package horcrux
+
+import "testing"
+
+var hmacKey = []byte("a very secret key")
+
+func TestFragmentHMAC(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.HMAC = f.ComputeHMAC(hmacKey)
+
+	if !f.VerifyHMAC(hmacKey) {
+		t.Fatal("HMAC failed verification")
+	}
+
+	if f.VerifyHMAC([]byte("another key")) {
+		t.Fatal("HMAC passed verification with the wrong key")
+	}
+
+	f.N = 2
+	if f.VerifyHMAC(hmacKey) {
+		t.Fatal("HMAC passed verification after modifying N")
+	}
+}
+
+func TestRecoverFragmentHMACKey(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		f := frags[i]
+		f.HMAC = f.ComputeHMAC(hmacKey)
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+
+	s, err := Recover(answers, WithFragmentHMACKey(hmacKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+
+	answers[1].N = 2
+	s, err = Recover(answers, WithFragmentHMACKey(hmacKey))
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
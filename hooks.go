@@ -0,0 +1,144 @@
+package horcrux
+
+import (
+	"sync"
+	"time"
+)
+
+// Hooks are functions called as secrets are split and recovered, which can be
+// used for logging or metrics. Any hook may be nil. Although keys are derived in
+// parallel, a split or recovery never calls its hooks concurrently.
+type Hooks struct {
+	// OnSplitStart is called before any fragments are encrypted.
+	OnSplitStart func(numFragments, k int)
+
+	// OnFragmentEncrypted is called after each fragment is encrypted, with the
+	// time taken to derive its key and encrypt its share.
+	OnFragmentEncrypted func(id int, elapsed time.Duration)
+
+	// OnRecoverStart is called before any answers are used.
+	OnRecoverStart func(numAnswers, k int)
+
+	// OnFragmentDecrypting is called before each attempt to decrypt a
+	// fragment's share.
+	OnFragmentDecrypting func(id int)
+
+	// OnFragmentDecrypted is called after each attempt to decrypt a fragment's
+	// share, with the time taken and whether or not it succeeded.
+	OnFragmentDecrypted func(id int, elapsed time.Duration, success bool)
+
+	// OnRecoverProgress is called by Recover after each answer's share is
+	// decrypted, with the number of answers done so far, the total, and an
+	// estimate of the time remaining based on the average time per answer.
+	OnRecoverProgress func(done, total int, remaining time.Duration)
+}
+
+var (
+	hooksMu sync.RWMutex
+	hooks   Hooks
+)
+
+// SetHooks sets the hooks used by every split and recovery which doesn't have
+// its own hooks set with WithSplitHooks or WithHooks.
+func SetHooks(h Hooks) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	hooks = h
+}
+
+// globalHooks returns the hooks set with SetHooks.
+func globalHooks() Hooks {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+
+	return hooks
+}
+
+func (h *Hooks) splitStart(numFragments, k int) {
+	if h.OnSplitStart != nil {
+		h.OnSplitStart(numFragments, k)
+	}
+}
+
+func (h *Hooks) fragmentEncrypted(id byte, start time.Time) {
+	if h.OnFragmentEncrypted != nil {
+		h.OnFragmentEncrypted(int(id), time.Since(start))
+	}
+}
+
+func (h *Hooks) recoverStart(numAnswers, k int) {
+	if h.OnRecoverStart != nil {
+		h.OnRecoverStart(numAnswers, k)
+	}
+}
+
+func (h *Hooks) fragmentDecrypting(id byte) {
+	if h.OnFragmentDecrypting != nil {
+		h.OnFragmentDecrypting(int(id))
+	}
+}
+
+func (h *Hooks) fragmentDecrypted(id byte, start time.Time, err error) {
+	if h.OnFragmentDecrypted != nil {
+		h.OnFragmentDecrypted(int(id), time.Since(start), err == nil)
+	}
+}
+
+func (h *Hooks) recoverProgress(done, total int, start time.Time) {
+	if h.OnRecoverProgress != nil {
+		perAnswer := time.Since(start) / time.Duration(done)
+		h.OnRecoverProgress(done, total, perAnswer*time.Duration(total-done))
+	}
+}
+
+// synchronized returns a copy of the hooks which never run concurrently, so
+// that hooks written for sequential splits and recoveries can be used with
+// parallel ones.
+func (h Hooks) synchronized() Hooks {
+	var mu sync.Mutex
+	s := Hooks{}
+	if fn := h.OnSplitStart; fn != nil {
+		s.OnSplitStart = func(numFragments, k int) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(numFragments, k)
+		}
+	}
+	if fn := h.OnFragmentEncrypted; fn != nil {
+		s.OnFragmentEncrypted = func(id int, elapsed time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(id, elapsed)
+		}
+	}
+	if fn := h.OnRecoverStart; fn != nil {
+		s.OnRecoverStart = func(numAnswers, k int) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(numAnswers, k)
+		}
+	}
+	if fn := h.OnFragmentDecrypting; fn != nil {
+		s.OnFragmentDecrypting = func(id int) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(id)
+		}
+	}
+	if fn := h.OnFragmentDecrypted; fn != nil {
+		s.OnFragmentDecrypted = func(id int, elapsed time.Duration, success bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(id, elapsed, success)
+		}
+	}
+	if fn := h.OnRecoverProgress; fn != nil {
+		s.OnRecoverProgress = func(done, total int, remaining time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			fn(done, total, remaining)
+		}
+	}
+	return s
+}
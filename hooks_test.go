@@ -0,0 +1,131 @@
+package horcrux
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	var splits, encrypted, recovers, decrypted int
+	h := Hooks{
+		OnSplitStart: func(numFragments, k int) {
+			splits++
+		},
+		OnFragmentEncrypted: func(id int, elapsed time.Duration) {
+			encrypted++
+		},
+		OnRecoverStart: func(numAnswers, k int) {
+			recovers++
+		},
+		OnFragmentDecrypted: func(id int, elapsed time.Duration, success bool) {
+			if !success {
+				t.Errorf("Expected fragment %d to be decrypted", id)
+			}
+			decrypted++
+		},
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithSplitHooks(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	if _, err := Recover(answers, WithHooks(h)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name             string
+		expected, actual int
+	}{
+		{"OnSplitStart", 1, splits},
+		{"OnFragmentEncrypted", len(questions), encrypted},
+		{"OnRecoverStart", 1, recovers},
+		{"OnFragmentDecrypted", 2, decrypted},
+	} {
+		if c.actual != c.expected {
+			t.Errorf("%s: Expected %v but was %v", c.name, c.expected, c.actual)
+		}
+	}
+}
+
+func TestRecoverProgressHooks(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 3)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	var started []int
+	var progress []int
+	h := Hooks{
+		OnFragmentDecrypting: func(id int) {
+			started = append(started, id)
+		},
+		OnRecoverProgress: func(done, total int, remaining time.Duration) {
+			if total != len(answers) {
+				t.Errorf("Expected %v but was %v", len(answers), total)
+			}
+
+			if done == total && remaining != 0 {
+				t.Errorf("Expected no time remaining but was %v", remaining)
+			}
+			progress = append(progress, done)
+		},
+	}
+
+	if _, err := Recover(answers, WithHooks(h)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(started) != len(answers) {
+		t.Fatalf("Expected %v but was %v", len(answers), len(started))
+	}
+
+	expected := []int{1, 2, 3}
+	if !reflect.DeepEqual(progress, expected) {
+		t.Fatalf("Expected %v but was %v", expected, progress)
+	}
+}
+
+func TestSetHooks(t *testing.T) {
+	defer SetHooks(Hooks{})
+
+	var failures int
+	SetHooks(Hooks{
+		OnFragmentDecrypted: func(id int, elapsed time.Duration, success bool) {
+			if !success {
+				failures++
+			}
+		},
+	})
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer("wrong"),
+		frags[1].WithAnswer("wrong"),
+	}
+
+	if _, err := Recover(answers); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	// every answer is tried, even after one is wrong
+	if failures != 2 {
+		t.Fatalf("Expected %v but was %v", 2, failures)
+	}
+}
@@ -13,38 +13,87 @@
 package horcrux
 
 import (
-	"crypto/rand"
+	"bytes"
+	"context"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/codahale/chacha20"
-	"github.com/codahale/chacha20poly1305"
 	"github.com/codahale/sss"
-	"golang.org/x/crypto/scrypt"
-)
-
-const (
-	saltLen = 32
+	"golang.org/x/text/unicode/norm"
 )
 
 // Fragment is an encrypted fragment of the secret associated with a security
 // question.
 type Fragment struct {
+	Version uint8 // Version is the fragment's format version.
+
 	ID byte // ID is a unique identifier for the fragment.
 	K  int  // K is the number of fragments required to recover the secret.
 	N  int  // N is the scrypt iteration parameter.
 	R  int  // R is the scrypt memory parameter.
 	P  int  // P is the scrypt parallelism parameter.
 
-	Question string // Question is the security question.
-	Nonce    []byte // Nonce is the random nonce used for encryption.
-	Salt     []byte // Salt is the random salt used for scrypt.
-	Value    []byte // Value is the encrypted share.
+	SetID  []byte // SetID identifies the split which produced the fragment.
+	Weight int    // Weight is the number of shares held, if more than one.
+
+	Group          int // Group is the fragment's group, if split into groups.
+	GroupThreshold int // GroupThreshold is the number of groups required.
+
+	Required bool // Required is true if recovery always needs the fragment.
+
+	BcryptCost int // BcryptCost is the bcrypt cost parameter.
+
+	Argon2Time    int // Argon2Time is the Argon2id time parameter.
+	Argon2Memory  int // Argon2Memory is the Argon2id memory parameter, in KiB.
+	Argon2Threads int // Argon2Threads is the Argon2id parallelism parameter.
+
+	KDFParams []byte // KDFParams are the parameters of a custom KDF.
+
+	Cipher string // Cipher is the share encryption algorithm.
+
+	KDF        string // KDF is the key derivation algorithm, e.g. "scrypt/v1".
+	Question   string // Question is the security question, or its hash.
+	AnswerHint string // AnswerHint is an optional plaintext hint for the answer.
+	Nonce      []byte // Nonce is the random nonce used for encryption.
+	Salt       []byte // Salt is the random salt used for key derivation.
+	Value      []byte // Value is the encrypted share.
+
+	EscrowedAnswer []byte // EscrowedAnswer is the answer, encrypted for escrow.
+
+	QuestionIsHashed bool   // QuestionIsHashed is true if Question is a hash.
+	BoundParams      bool   // BoundParams is true if the share authenticates the question and parameters.
+	NormalizerID     string // NormalizerID names the answer normalizer used.
+	Locale           string // Locale is the language tag used for case folding.
+	AnswerSeparator  string // AnswerSeparator joins compound answer parts.
+
+	Alternates []Alternate // Alternates hold the share for other answers.
+
+	Checksum          [4]byte // Checksum detects corruption of Nonce, Salt, and Value.
+	StructureChecksum []byte  // StructureChecksum detects corruption of other fields.
+
+	MaxAttempts  int // MaxAttempts limits answer attempts, if positive.
+	AttemptCount int // AttemptCount is the number of failed attempts.
+
+	Metadata map[string]string // Metadata is arbitrary, unencrypted metadata.
+	HMAC     []byte            // HMAC authenticates all other fields.
+
+	Revoked             bool   // Revoked is true if the fragment was revoked, which is advisory.
+	RevocationSignature []byte // RevocationSignature signs the revocation.
 }
 
+// String returns the fragment's text form, which ParseFragment parses.
 func (f Fragment) String() string {
-	return fmt.Sprintf("%d/%d:%s:%d:%d:%d:%x:%x",
-		f.ID, f.K, f.Question, f.N, f.R, f.P, f.Salt, f.Value)
+	s := fmt.Sprintf("%d/%d:%s:%d:%d:%d:%x:%x",
+		f.ID, f.K, escapeText(f.Question), f.N, f.R, f.P, f.Salt, f.Value)
+	if f.AnswerHint != "" {
+		s += ":" + escapeText(f.AnswerHint)
+	}
+	return s
 }
 
 // Answer is an encrypted fragment of the secret, plus the answer to the
@@ -54,8 +103,63 @@ type Answer struct {
 	Answer   string // Answer is the answer to the security question.
 }
 
+// String returns the answer's text form, which ParseAnswer parses.
 func (f Answer) String() string {
-	return fmt.Sprintf("%v:%s", f.Fragment, f.Answer)
+	return fmt.Sprintf("%v:%s", f.Fragment, escapeText(f.Answer))
+}
+
+// WithQuestion returns a copy of the fragment with the given question. The
+// copy shares no memory with the original.
+func (f Fragment) WithQuestion(q string) Fragment {
+	f = f.Clone()
+	f.Question = q
+	return f
+}
+
+// WithAnswer returns an answer to a copy of the fragment. The copy shares no
+// memory with the original.
+func (f Fragment) WithAnswer(a string) Answer {
+	return Answer{Fragment: f.Clone(), Answer: a}
+}
+
+// Clone returns a copy of the fragment which shares no memory with the
+// original, including its metadata.
+func (f Fragment) Clone() Fragment {
+	if f.Metadata != nil {
+		m := make(map[string]string, len(f.Metadata))
+		for k, v := range f.Metadata {
+			m[k] = v
+		}
+		f.Metadata = m
+	}
+	f.Nonce = cloneBytes(f.Nonce)
+	f.Salt = cloneBytes(f.Salt)
+	f.Value = cloneBytes(f.Value)
+	f.SetID = cloneBytes(f.SetID)
+	f.StructureChecksum = cloneBytes(f.StructureChecksum)
+	if f.Alternates != nil {
+		alts := make([]Alternate, len(f.Alternates))
+		for i, alt := range f.Alternates {
+			alts[i] = Alternate{
+				Salt:  cloneBytes(alt.Salt),
+				Nonce: cloneBytes(alt.Nonce),
+				Value: cloneBytes(alt.Value),
+			}
+		}
+		f.Alternates = alts
+	}
+	f.EscrowedAnswer = cloneBytes(f.EscrowedAnswer)
+	f.RevocationSignature = cloneBytes(f.RevocationSignature)
+	f.HMAC = cloneBytes(f.HMAC)
+	return f
+}
+
+// cloneBytes returns a copy of b, preserving nil.
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
 }
 
 // Split splits the given secret into encrypted fragments based on the given
@@ -63,89 +167,676 @@ func (f Answer) String() string {
 // secret. n is the scrypt iteration parameter, and should be set fairly high
 // due to the low entropy of most security question answers (recommended: 2<<14).
 // r is the scrypt memory parameter (recommended: 8). p is the scrypt parallelism
-// parameter (recommended: 1). Returns either a slice of fragments or an error.
-func Split(secret []byte, questions map[string]string, k, n, r, p int) ([]Fragment, error) {
-	shares, err := sss.Split(byte(len(questions)), byte(k), secret)
+// parameter (recommended: 1). If n, r, and p are all zero, DefaultScryptParams
+// are used instead. Answers are normalized with FoldNormalizer unless another
+// normalizer is given. Returns either a slice of fragments or an error. See
+// also SplitWithOptions.
+func Split(secret []byte, questions map[string]string, k, n, r, p int, opts ...SplitOption) ([]Fragment, error) {
+	cfg := splitConfig{kdf: KDFScrypt, n: n, r: r, p: p,
+		normalizerID: DefaultNormalizerID}
+	if n == 0 && r == 0 && p == 0 {
+		cfg = defaultSplitConfig()
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	res, err := split(secret, questions, k, &cfg)
 	if err != nil {
 		return nil, err
 	}
+	return res.Fragments, nil
+}
 
-	f := make([]Fragment, 0, len(questions))
+// ErrFragmentTooLarge is returned by Split when the binary encoding of a
+// fragment is larger than the limit set with WithMaxFragmentSize.
+type ErrFragmentTooLarge struct {
+	ID   int // ID is the ID of the fragment.
+	Size int // Size is the size of the fragment's binary encoding.
+}
 
-	i := byte(1)
-	for q, a := range questions {
-		salt := make([]byte, saltLen)
-		_, err := io.ReadFull(rand.Reader, salt)
-		if err != nil {
-			return nil, err
+func (e ErrFragmentTooLarge) Error() string {
+	return fmt.Sprintf("horcrux: fragment %d is too large (%d bytes)",
+		e.ID, e.Size)
+}
+
+// SplitResult is the full result of splitting a secret.
+type SplitResult struct {
+	Fragments      []Fragment // Fragments are the encrypted fragments.
+	ExtraFragments []Fragment // ExtraFragments are the extra fragments, if any.
+	Commitments    [][]byte   // Commitments are the share commitments, if any.
+}
+
+// SplitWithResult splits the given secret like Split, but returns the full
+// result of the split, including any share commitments. Unless overridden with
+// WithScryptParams, the recommended scrypt parameters are used.
+func SplitWithResult(secret []byte, questions map[string]string, k int, opts ...SplitOption) (SplitResult, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return split(secret, questions, k, &cfg)
+}
+
+// SplitWithOptions splits the given secret like Split, but takes every
+// parameter other than k from options, which are validated before any keys are
+// derived. Unless overridden, DefaultScryptParams are used. This avoids mixing
+// up Split's positional scrypt parameters, and works with any KDF.
+func SplitWithOptions(secret []byte, questions map[string]string, k int, opts ...SplitOption) ([]Fragment, error) {
+	res, err := SplitWithResult(secret, questions, k, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return res.Fragments, nil
+}
+
+func split(secret []byte, questions map[string]string, k int, cfg *splitConfig) (SplitResult, error) {
+	questions, err := normalizeQuestions(questions)
+	if err != nil {
+		return SplitResult{}, err
+	}
+
+	if cfg.requiredQuestion != "" {
+		return splitRequired(secret, questions, k, cfg)
+	}
+
+	shares, extra, err := shareSecret(secret, questions, k, cfg)
+	if err != nil {
+		return SplitResult{}, err
+	}
+	defer zeroShares(shares)
+	defer zeroShares(extra)
+
+	res, err := encryptShares(shares, questions, k, cfg)
+	if err != nil {
+		return res, err
+	}
+
+	if len(extra) > 0 {
+		err = encryptExtraShares(&res, extra, k, cfg)
+	}
+	return res, err
+}
+
+// shareSecret splits the secret into a share for each unit of the given
+// questions' weight, plus any extra shares. The questions must already be
+// normalized. The extra shares, if any, have the highest IDs, and are returned
+// separately.
+func shareSecret(secret []byte, questions map[string]string, k int, cfg *splitConfig) (shares, extra map[byte][]byte, err error) {
+	n := cfg.totalWeight(questions) + cfg.extraShares
+	if n > 255 {
+		return nil, nil, fmt.Errorf("horcrux: too many shares (%d)", n)
+	}
+
+	if cfg.extraShares > 0 && len(cfg.extraShareKey) != chacha20.KeySize {
+		return nil, nil, fmt.Errorf(
+			"horcrux: extra share key must be %d bytes", chacha20.KeySize)
+	}
+
+	shares, err = sss.Split(byte(n), byte(k), secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shares, takeExtraShares(shares, cfg.extraShares), nil
+}
+
+// encryptShares encrypts one share for each of the given questions, which must
+// already be normalized. Shares are assigned to questions in order of ID.
+func encryptShares(shares map[byte][]byte, questions map[string]string, k int, cfg *splitConfig) (SplitResult, error) {
+	var res SplitResult
+
+	frags, commitments, err := encryptSharesTo(shares, questions, k, cfg,
+		func() *Fragment { return &Fragment{} })
+	if err != nil {
+		return res, err
+	}
+
+	res.Fragments = make([]Fragment, len(frags))
+	for i, f := range frags {
+		res.Fragments[i] = *f
+	}
+	res.Commitments = commitments
+	return res, nil
+}
+
+// encryptSharesTo encrypts the shares like encryptShares, writing each
+// fragment to one returned by alloc, and reusing that fragment's byte slices
+// where possible.
+func encryptSharesTo(shares map[byte][]byte, questions map[string]string, k int, cfg *splitConfig, alloc func() *Fragment) ([]*Fragment, [][]byte, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if need := cfg.totalWeight(questions); len(shares) != need {
+		if need != len(questions) {
+			return nil, nil, fmt.Errorf(
+				"horcrux: have %d shares but weighted questions need %d",
+				len(shares), need)
 		}
+		return nil, nil, fmt.Errorf("horcrux: have %d shares but %d questions",
+			len(shares), len(questions))
+	}
+
+	ids := make([]byte, 0, len(shares))
+	for id := range shares {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
 
-		frag := Fragment{
-			N:        n,
-			R:        r,
-			P:        p,
-			ID:       i,
-			K:        k,
-			Salt:     salt,
-			Question: q,
+	var commitments [][]byte
+	if cfg.commitments && len(ids) > 0 {
+		commitments = make([][]byte, ids[len(ids)-1])
+		for id, share := range shares {
+			c, err := commit(id, share)
+			if err != nil {
+				return nil, nil, err
+			}
+			commitments[id-1] = c
 		}
+	}
+
+	normalize, err := cfg.normalizer()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkAnswerLengths(questions, normalize, cfg.minAnswerLength); err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkAnswerStrengths(questions, normalize, cfg); err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := cfg.rand.(*seededReader); ok {
+		warnf("splitting a secret with non-cryptographic randomness")
+	}
 
-		k, err := scrypt.Key([]byte(a), salt, frag.N, frag.R, frag.P, chacha20.KeySize)
+	// every fragment of a split shares its set ID, so fragments of different
+	// splits can't be combined
+	if cfg.setID == nil {
+		setID, err := newSetID(cfg.random())
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
+		scfg := *cfg
+		scfg.setID = setID
+		cfg = &scfg
+	}
 
-		aead, err := chacha20poly1305.New(k)
-		if err != nil {
-			return nil, err
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	h := cfg.getHooks().synchronized()
+	h.splitStart(len(questions), k)
+
+	f := make([]*Fragment, 0, len(questions))
+	qs := make([]string, 0, len(questions))
+	for q := range questions {
+		f = append(f, alloc())
+		qs = append(qs, q)
+	}
+
+	// each question is given as many consecutive shares as its weight, which
+	// are encrypted together
+	first := make([]byte, len(qs))
+	values := make([][]byte, len(qs))
+	defer func() {
+		for j, q := range qs {
+			if cfg.weight(q) > 1 {
+				zero(values[j])
+			}
+		}
+	}()
+	for j, pos := 0, 0; j < len(qs); j++ {
+		first[j] = ids[pos]
+		values[j] = shares[ids[pos]]
+		if w := cfg.weight(qs[j]); w > 1 {
+			if values[j], err = bundleShares(shares, ids[pos], w); err != nil {
+				return f, nil, err
+			}
+		}
+		pos += cfg.weight(qs[j])
+	}
+
+	// a custom source of randomness may not be safe for concurrent use, and
+	// should produce the same fragments every time
+	limit := workers(cfg.concurrency)
+	if cfg.rand != nil {
+		limit = 1
+	}
+
+	err = parallel(len(f), limit, func(j int) error {
+		i, q, a := first[j], qs[j], questions[qs[j]]
+		start := time.Now()
+
+		answer := a
+		if normalize != nil {
+			answer = normalize(a)
 		}
 
-		frag.Nonce = make([]byte, aead.NonceSize())
-		_, err = io.ReadFull(rand.Reader, frag.Nonce)
+		err := encryptShare(ctx, f[j], i, k, q, answer, a, values[j], cfg)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		frag.Value = aead.Seal(nil, frag.Nonce, shares[i], nil)
+		for _, alt := range cfg.alternates[q] {
+			if normalize != nil {
+				alt = normalize(alt)
+			}
 
-		f = append(f, frag)
+			if err := addAlternate(ctx, f[j], q, alt, values[j], cfg); err != nil {
+				return err
+			}
+		}
 
-		i++
+		h.fragmentEncrypted(f[j].ID, start)
+		return nil
+	})
+	if err != nil {
+		return f, nil, err
 	}
 
-	return f, nil
+	return f, commitments, nil
 }
 
-// Recover combines the given answers and returns the original secret or an
-// error.
-func Recover(answers []Answer) ([]byte, error) {
-	shares := make(map[byte][]byte)
+// encryptShare derives a key from the normalized answer and encrypts the share
+// with it, overwriting frag. The original answer is used for escrow.
+func encryptShare(ctx context.Context, frag *Fragment, id byte, k int, q, answer, original string, share []byte, cfg *splitConfig) error {
+	// a required fragment is only enforced if it's in a group of its own
+	if cfg.requiredQuestion != "" && cfg.group == 0 {
+		return errRequiredQuestion
+	}
+
+	*frag = Fragment{
+		Version:    FragmentVersion2,
+		N:          cfg.n,
+		R:          cfg.r,
+		P:          cfg.p,
+		ID:         id,
+		SetID:      cfg.setID,
+		Weight:     cfg.weights[q],
+		K:          k,
+		KDF:        cfg.kdf,
+		BcryptCost: cfg.bcryptCost,
+
+		Argon2Time:    cfg.argon2Time,
+		Argon2Memory:  cfg.argon2Memory,
+		Argon2Threads: cfg.argon2Threads,
+
+		Group:          cfg.group,
+		GroupThreshold: cfg.groupThreshold,
+		Required:       q != "" && q == cfg.requiredQuestion,
+
+		BoundParams: true,
+
+		Salt:       resize(frag.Salt, saltLen),
+		Nonce:      frag.Nonce,
+		Value:      frag.Value[:0],
+		Question:   q,
+		AnswerHint: cfg.hints[q],
+
+		NormalizerID:    cfg.normalizerID,
+		Locale:          cfg.locale,
+		AnswerSeparator: cfg.answerSeparator,
+	}
+
+	_, err := io.ReadFull(cfg.random(), frag.Salt)
+	if err != nil {
+		return err
+	}
+
+	if cfg.customKDF != nil {
+		frag.KDFParams, err = cfg.customKDF.MarshalParams()
+		if err != nil {
+			return err
+		}
+	}
+
+	key, err := deriveKeyContext(ctx, *frag, answer)
+	if err != nil {
+		return err
+	}
+	defer zero(key)
+
+	aead, name, err := cfg.newAEAD(key)
+	if err != nil {
+		return err
+	}
+	frag.Cipher = name
+	frag.Nonce = resize(frag.Nonce, aead.NonceSize())
 
-	for _, a := range answers {
-		if a.K > len(answers) {
-			return nil, fmt.Errorf(
-				"horcrux: need at least %d answers but only have %d",
-				a.K, len(answers))
+	_, err = io.ReadFull(cfg.random(), frag.Nonce)
+	if err != nil {
+		return err
+	}
+
+	ad, err := frag.associatedData(q)
+	if err != nil {
+		return err
+	}
+
+	if cfg.privateQuestions {
+		frag.Question = hex.EncodeToString(hashQuestion(q))
+		frag.QuestionIsHashed = true
+	}
+
+	frag.Value = aead.Seal(frag.Value, frag.Nonce, share, ad)
+	frag.Checksum = frag.ComputeChecksum()
+	frag.StructureChecksum = frag.ComputeStructureChecksum()
+
+	if err := checkFragment(frag); err != nil {
+		return err
+	}
+
+	if cfg.escrowKey != nil {
+		// the stored question, which is hashed if private, is what
+		// RecoverEscrowedAnswer has to authenticate with
+		frag.EscrowedAnswer, err = escrowAnswer(cfg.escrowKey, frag.Question, original)
+		if err != nil {
+			return err
 		}
+	}
 
-		k, err := scrypt.Key([]byte(a.Answer), a.Salt, a.N, a.R, a.P,
-			chacha20.KeySize)
+	if cfg.maxFragmentSize > 0 {
+		b, err := frag.MarshalBinary()
 		if err != nil {
+			return err
+		}
+
+		if len(b) > cfg.maxFragmentSize {
+			return ErrFragmentTooLarge{ID: int(frag.ID), Size: len(b)}
+		}
+	}
+
+	return nil
+}
+
+// resize returns a slice of length n, reusing b's storage if it is big enough.
+func resize(b []byte, n int) []byte {
+	if cap(b) >= n {
+		return b[:n]
+	}
+	return make([]byte, n)
+}
+
+// Recover combines the given answers and returns the original secret or an
+// error. Answers to fragments from different splits are refused with
+// ErrMixedSets.
+func Recover(answers []Answer, opts ...RecoverOption) ([]byte, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return recoverSecret(answers, &cfg)
+}
+
+// RecoverTo combines the given answers like Recover, but writes the secret to w
+// instead of returning it. The secret is zeroed once it has been written.
+// Returns the number of bytes written.
+func RecoverTo(answers []Answer, w io.Writer, opts ...RecoverOption) (int, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	s, err := recoverSecret(answers, &cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer zero(s)
+
+	n, err := io.Copy(w, bytes.NewReader(s))
+	return int(n), err
+}
+
+func recoverSecret(answers []Answer, cfg *recoverConfig) ([]byte, error) {
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	h := cfg.getHooks().synchronized()
+	cfg.hooks = &h
+	if len(answers) > 0 {
+		h.recoverStart(len(answers), answers[0].K)
+	}
+
+	if cfg.strictKDF {
+		if err := checkKDFs(answers); err != nil {
 			return nil, err
 		}
+	}
 
-		aead, err := chacha20poly1305.New(k)
-		if err != nil {
+	if err := checkSets(answers); err != nil {
+		return nil, err
+	}
+
+	if grouped(answers) {
+		if err := checkGroups(answers); err != nil {
 			return nil, err
 		}
+	} else {
+		have := answersWeight(answers)
+		for _, a := range answers {
+			if a.K > have {
+				return nil, ErrTooFewAnswers{Need: a.K, Have: have}
+			}
+		}
+	}
+
+	shares := make([]share, len(answers))
+	start := time.Now()
+
+	var mu sync.Mutex
+	done := 0
+
+	// wrong answers don't stop the other answers from being tried, so that
+	// every wrong answer can be reported at once
+	wrong := make([]error, len(answers))
+
+	err := parallel(len(answers), workers(cfg.concurrency), func(i int) error {
+		s, err := openShare(ctx, answers[i], cfg)
+		if isAuthFailure(err) {
+			wrong[i] = err
+			return nil
+		} else if err != nil {
+			return err
+		}
+		shares[i] = s
+
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		h.recoverProgress(done, len(answers), start)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer wipeShares(shares)
+
+	if err := answersFailed(shares, wrong); err != nil {
+		return nil, err
+	}
+
+	if grouped(answers) {
+		return combineGroups(answers, shares)
+	}
+
+	k := 0
+	if len(answers) > 0 {
+		k = answers[0].K
+	}
+	return safeCombine(shares, k)
+}
+
+// openShare derives the answer's key and decrypts its share. If typo tolerance
+// is enabled and the answer is wrong, variations of it are tried as well.
+func openShare(ctx context.Context, a Answer, cfg *recoverConfig) (share, error) {
+	s, err := openAnswer(ctx, a, cfg)
+
+	if err == nil || cfg.typoBudget <= 0 || !isAuthFailure(err) {
+		return s, err
+	}
+
+	if s, ok := openCandidates(ctx, a, cfg); ok {
+		return s, nil
+	}
+	return share{}, err
+}
+
+// openAnswer derives the answer's key and decrypts its share.
+func openAnswer(ctx context.Context, a Answer, cfg *recoverConfig) (s share, err error) {
+	h := cfg.getHooks()
+	h.fragmentDecrypting(a.ID)
+	start := time.Now()
+	defer func() { h.fragmentDecrypted(a.ID, start, err) }()
+
+	if cfg.hmacKey != nil && !a.VerifyHMAC(cfg.hmacKey) {
+		return share{}, ErrCorruptFragment{
+			ID:     int(a.ID),
+			Reason: "failed HMAC verification",
+		}
+	}
+
+	if err := cfg.checkCipher(a.Fragment); err != nil {
+		return share{}, err
+	}
+
+	if a.MaxAttempts > 0 && a.AttemptCount >= a.MaxAttempts {
+		return share{}, ErrTooManyAttempts{ID: int(a.ID)}
+	}
 
-		v, err := aead.Open(nil, a.Nonce, a.Value, nil)
+	if a.Checksum != [4]byte{} && !a.ValidateChecksum() {
+		return share{}, ErrCorruptFragment{ID: int(a.ID), Reason: "failed checksum"}
+	}
+
+	if a.StructureChecksum != nil && !a.ValidateStructureChecksum() {
+		return share{}, ErrCorruptFragment{
+			ID:     int(a.ID),
+			Reason: "failed structure checksum",
+		}
+	}
+
+	if err := checkRevocation(a.Fragment, cfg.revocationKey); err != nil {
+		return share{}, err
+	}
+
+	if err := checkRevocationList(a.Fragment, cfg.revoked, cfg.revocationKey); err != nil {
+		return share{}, err
+	}
+
+	answer := a.Answer
+	if a.NormalizerID != "" {
+		if fn, ok := LookupNormalizer(a.NormalizerID); ok {
+			answer = localize(fn, a.Locale)(answer)
+		} else {
+			cfg.logf("horcrux: warning: fragment %d uses unregistered normalizer %q",
+				a.ID, a.NormalizerID)
+		}
+	}
+
+	ad, err := a.associatedData(a.Question)
+	if err != nil {
+		return share{}, err
+	}
+
+	v, err := openValue(ctx, a.Fragment, answer, ad, cfg)
+
+	// if the answer is wrong, it may be one of the alternate answers
+	for _, alt := range a.Alternates {
+		if !isAuthFailure(err) {
+			break
+		}
+
+		av, aerr := openValue(ctx, alt.fragment(a.Fragment), answer, ad, cfg)
+		if !isAuthFailure(aerr) {
+			v, err = av, aerr
+		}
+	}
+	if err != nil {
+		return share{}, err
+	}
+
+	return unbundleShare(a.Fragment, v, cfg.commitments)
+}
+
+// openValue derives the key for the fragment from the normalized answer, using
+// the KDF cache if there is one, and decrypts the fragment's share with it.
+func openValue(ctx context.Context, f Fragment, answer string, ad []byte, cfg *recoverConfig) ([]byte, error) {
+	var hash [32]byte
+	var k []byte
+	cached := false
+	if cfg.kdfCache != nil {
+		hash = answerHash(cacheHashKey(cfg.kdfCache), f, answer)
+		k, cached = cfg.kdfCache.Get(int(f.ID), hash)
+
+		// the cache may have returned its own copy of the key, which mustn't be
+		// zeroed
+		k = cloneBytes(k)
+	}
+
+	if !cached {
+		var err error
+		k, err = deriveKeyContext(ctx, f, answer)
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	defer zero(k)
+
+	v, err := cfg.open(ctx, f, k, ad)
+	if err != nil {
+		return nil, ErrAuthenticationFailed{ID: int(f.ID), Cause: err}
+	}
+
+	if cfg.kdfCache != nil && !cached {
+		cfg.kdfCache.Set(int(f.ID), hash, k)
+	}
+	return v, nil
+}
+
+// IsReadyForRecovery returns true if enough of the given fragments' questions
+// have answers available to recover the secret.
+func IsReadyForRecovery(frags []Fragment, answersAvailable map[string]string) bool {
+	if len(frags) == 0 {
+		return false
+	}
+
+	matching := 0
+	var available []Answer
+	for _, f := range frags {
+		if _, ok := answersAvailable[f.Question]; ok {
+			matching += f.weight()
+			available = append(available, Answer{Fragment: f})
+		}
+	}
 
-		shares[a.ID] = v
+	if grouped(available) {
+		return checkGroups(available) == nil
 	}
 
-	return sss.Combine(shares), nil
+	return matching >= frags[0].K
+}
+
+// normalizeQuestions returns the questions in Unicode Normalization Form C,
+// returning an error if any two questions are the same once normalized.
+func normalizeQuestions(questions map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(questions))
+	for q, a := range questions {
+		q = norm.NFC.String(q)
+		if _, ok := normalized[q]; ok {
+			return nil, fmt.Errorf("horcrux: duplicate question %q", q)
+		}
+		normalized[q] = a
+	}
+	return normalized, nil
+}
+
+// zero overwrites b with zeros.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
 }
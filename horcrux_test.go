@@ -1,7 +1,11 @@
 package horcrux
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -115,7 +119,7 @@ func TestSplitBadScryptParams(t *testing.T) {
 		t.Fatalf("Expected error but got %v", frags)
 	}
 
-	expected := "scrypt: N must be > 1 and a power of 2"
+	expected := "horcrux: key derivation failed: scrypt N must be > 1 and a power of 2"
 	actual := err.Error()
 	if actual != expected {
 		t.Fatalf("Expected %v but was %v", expected, actual)
@@ -167,9 +171,376 @@ func TestRecoverBadAnswers(t *testing.T) {
 		t.Fatalf("Expected nil, but was %v", s)
 	}
 
-	expected := "message authentication failed"
+	expected := "horcrux: wrong answers for fragments [1 2] (decrypted [])"
 	actual := err.Error()
 	if actual != expected {
 		t.Fatalf("Expected %v but was %v", expected, actual)
 	}
 }
+
+func TestFragmentStringerWithHint(t *testing.T) {
+	f := Fragment{
+		ID:         1,
+		N:          2,
+		R:          3,
+		P:          4,
+		K:          5,
+		Question:   "Q",
+		AnswerHint: "H",
+		Nonce:      []byte{10},
+		Salt:       []byte{11},
+		Value:      []byte{12},
+	}
+
+	expected := "1/5:Q:2:3:4:0b:0c:H"
+	actual := f.String()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestSplitWithOptions(t *testing.T) {
+	frags, err := SplitWithOptions(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.N != 2<<10 || f.R != 8 || f.P != 1 {
+			t.Fatalf("Expected scrypt parameters but was %v", f)
+		}
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestSplitWithOptionsValidates(t *testing.T) {
+	called := false
+	old := derive
+	defer func() { derive = old }()
+	derive = func(f Fragment, answer string) ([]byte, error) {
+		called = true
+		return old(f, answer)
+	}
+
+	frags, err := SplitWithOptions(secret, questions, 2,
+		WithScryptParams(2<<10, 0, 1))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := "horcrux: key derivation failed: bad scrypt parameters R=0, P=1"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+
+	if called {
+		t.Fatal("Expected no key derivation but one was attempted")
+	}
+}
+
+func TestSplitAnswerHint(t *testing.T) {
+	q := "What's your first pet's name?"
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAnswerHint(q, "first two letters: SP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		expected := ""
+		if f.Question == q {
+			expected = "first two letters: SP"
+		}
+
+		if f.AnswerHint != expected {
+			t.Fatalf("Expected hint %q for %q but was %q",
+				expected, f.Question, f.AnswerHint)
+		}
+
+		b, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual Fragment
+		if err := json.Unmarshal(b, &actual); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, f) {
+			t.Fatalf("Expected %v but was %v", f, actual)
+		}
+	}
+}
+
+func TestRecoverIgnoresAnswerHint(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAnswerHint("What's your first pet's name?", "SP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		f := frags[i]
+		f.AnswerHint = "not even close"
+		answers[i] = Answer{
+			Fragment: f,
+			Answer:   questions[f.Question],
+		}
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestIsReadyForRecovery(t *testing.T) {
+	frags := []Fragment{
+		{ID: 1, K: 2, Question: "A"},
+		{ID: 2, K: 2, Question: "B"},
+		{ID: 3, K: 2, Question: "C"},
+	}
+
+	if !IsReadyForRecovery(frags, map[string]string{"A": "a", "C": "c"}) {
+		t.Fatal("Expected to be ready with 2 of 3 answers")
+	}
+
+	if IsReadyForRecovery(frags, map[string]string{"A": "a", "D": "d"}) {
+		t.Fatal("Expected not to be ready with 1 of 3 answers")
+	}
+
+	if IsReadyForRecovery(nil, map[string]string{"A": "a"}) {
+		t.Fatal("Expected not to be ready without fragments")
+	}
+}
+
+func TestSplitNormalizesQuestions(t *testing.T) {
+	frags, err := Split(secret, map[string]string{
+		"Cafe\u0301?": "latte",
+		"Tea?":        "green",
+	}, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.Question != "Tea?" && f.Question != "Caf\u00e9?" {
+			t.Fatalf("Expected an NFC question but was %q", f.Question)
+		}
+	}
+}
+
+func TestSplitDuplicateQuestions(t *testing.T) {
+	frags, err := Split(secret, map[string]string{
+		"Caf\u00e9?":  "latte",
+		"Cafe\u0301?": "mocha",
+	}, 2, 2<<10, 8, 1)
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := "horcrux: duplicate question \"Caf\u00e9?\""
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestFragmentWithQuestion(t *testing.T) {
+	f := Fragment{
+		ID:       1,
+		Question: "Q",
+		Nonce:    []byte{10},
+		Salt:     []byte{11},
+		Value:    []byte{12},
+	}
+
+	g := f.WithQuestion("R")
+	g.Value[0] = 99
+
+	if f.Question != "Q" {
+		t.Fatalf("Expected %v but was %v", "Q", f.Question)
+	}
+
+	if f.Value[0] != 12 {
+		t.Fatalf("Expected %v but was %v", 12, f.Value[0])
+	}
+
+	if g.Question != "R" {
+		t.Fatalf("Expected %v but was %v", "R", g.Question)
+	}
+}
+
+func TestFragmentWithAnswer(t *testing.T) {
+	f := Fragment{
+		ID:       1,
+		Question: "Q",
+		Nonce:    []byte{10},
+		Salt:     []byte{11},
+		Value:    []byte{12},
+	}
+
+	a := f.WithAnswer("A")
+	a.Salt[0] = 99
+
+	if f.Salt[0] != 11 {
+		t.Fatalf("Expected %v but was %v", 11, f.Salt[0])
+	}
+
+	if a.Answer != "A" {
+		t.Fatalf("Expected %v but was %v", "A", a.Answer)
+	}
+}
+
+func TestFragmentWithQuestionConcurrent(t *testing.T) {
+	f := Fragment{
+		ID:       1,
+		Question: "Q",
+		Nonce:    []byte{10},
+		Salt:     []byte{11},
+		Value:    []byte{12},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			g := f.WithQuestion(fmt.Sprint(i))
+			g.Value[0] = byte(i)
+			a := f.WithAnswer(fmt.Sprint(i))
+			a.Nonce[0] = byte(i)
+		}(i)
+	}
+	wg.Wait()
+
+	if f.Value[0] != 12 || f.Nonce[0] != 10 {
+		t.Fatalf("Original fragment was modified: %v", f)
+	}
+}
+
+func TestFragmentClone(t *testing.T) {
+	f := Fragment{
+		ID:       1,
+		Question: "Q",
+		Value:    []byte{12},
+		Metadata: map[string]string{"owner": "alice"},
+	}
+
+	g := f.Clone()
+	g.Metadata["owner"] = "bob"
+	g.Metadata["added"] = "yes"
+
+	if !reflect.DeepEqual(f.Metadata, map[string]string{"owner": "alice"}) {
+		t.Fatalf("Original metadata was modified: %v", f.Metadata)
+	}
+
+	f.Metadata["owner"] = "carol"
+	if g.Metadata["owner"] != "bob" {
+		t.Fatalf("Clone metadata was modified: %v", g.Metadata)
+	}
+
+	f.Value[0] = 99
+	if g.Value[0] != 12 {
+		t.Fatalf("Clone value was modified: %v", g.Value)
+	}
+}
+
+func TestRecoverTo(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	var buf bytes.Buffer
+	n, err := RecoverTo(answers, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(secret) {
+		t.Fatalf("Expected %d bytes but was %d", len(secret), n)
+	}
+
+	if buf.String() != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, buf.String())
+	}
+}
+
+func TestSplitRecordsCipher(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.Cipher != CipherChaCha20Poly1305 {
+			t.Fatalf("Expected %v but was %v", CipherChaCha20Poly1305, f.Cipher)
+		}
+	}
+
+	// fragments written before the cipher was recorded still decrypt
+	answers := make([]Answer, 2)
+	for i := range answers {
+		f := frags[i]
+		f.Cipher = ""
+		f.StructureChecksum = nil
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestRecoverUnsupportedCipher(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.Cipher = "AES-GCM"
+	answers := []Answer{
+		f.WithAnswer(questions[f.Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	_, err = Recover(answers)
+
+	expected := `horcrux: fragment 1 uses unsupported cipher "AES-GCM"`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
@@ -0,0 +1,123 @@
+package horcrux
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The JSON encoding of a fragment uses its field names as keys. Byte slices,
+// including the checksum, are encoded as base64 strings, and the version is
+// always explicit.
+
+// jsonFragment has a Fragment's fields but not its JSON methods.
+type jsonFragment Fragment
+
+// fragmentJSON is the JSON form of a fragment. Its Version and Checksum fields
+// take precedence over those of the embedded fragment.
+type fragmentJSON struct {
+	jsonFragment
+	Version  uint8
+	Checksum json.RawMessage `json:",omitempty"`
+}
+
+func newFragmentJSON(f Fragment) (fragmentJSON, error) {
+	v := fragmentJSON{jsonFragment: jsonFragment(f), Version: f.version()}
+	if f.Checksum != [4]byte{} {
+		c, err := json.Marshal(f.Checksum[:])
+		if err != nil {
+			return v, err
+		}
+		v.Checksum = c
+	}
+	return v, nil
+}
+
+func (v fragmentJSON) fragment() (Fragment, error) {
+	f := Fragment(v.jsonFragment)
+
+	if v.Version > FragmentVersion2 {
+		return f, fmt.Errorf("horcrux: unknown fragment version %d", v.Version)
+	}
+	f.Version = v.Version
+
+	f.Checksum = [4]byte{}
+	if len(v.Checksum) > 0 && !bytes.Equal(v.Checksum, []byte("null")) {
+		var s string
+		if err := json.Unmarshal(v.Checksum, &s); err != nil {
+			return f, err
+		}
+
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return f, err
+		}
+
+		if len(b) != len(f.Checksum) {
+			return f, errors.New("horcrux: bad checksum in fragment")
+		}
+		copy(f.Checksum[:], b)
+	}
+
+	return f, nil
+}
+
+// MarshalJSON returns the fragment's JSON encoding.
+func (f Fragment) MarshalJSON() ([]byte, error) {
+	v, err := newFragmentJSON(f)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes the fragment from its JSON encoding.
+func (f *Fragment) UnmarshalJSON(data []byte) error {
+	var v fragmentJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	g, err := v.fragment()
+	if err != nil {
+		return err
+	}
+
+	*f = g
+	return nil
+}
+
+// answerJSON is the JSON form of an answer: its fragment's fields, plus the
+// answer itself.
+type answerJSON struct {
+	fragmentJSON
+	Answer string
+}
+
+// MarshalJSON returns the answer's JSON encoding, which is that of its fragment
+// with an additional Answer field.
+func (a Answer) MarshalJSON() ([]byte, error) {
+	v, err := newFragmentJSON(a.Fragment)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(answerJSON{fragmentJSON: v, Answer: a.Answer})
+}
+
+// UnmarshalJSON decodes the answer from its JSON encoding.
+func (a *Answer) UnmarshalJSON(data []byte) error {
+	var v answerJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	f, err := v.fragment()
+	if err != nil {
+		return err
+	}
+
+	*a = Answer{Fragment: f, Answer: v.Answer}
+	return nil
+}
@@ -0,0 +1,107 @@
+package horcrux
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFragmentJSON(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.Metadata = map[string]string{"owner": "me"}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := m["Checksum"].(string); !ok {
+		t.Fatalf("Expected a base64 checksum but was %v", m["Checksum"])
+	}
+
+	var actual Fragment
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, f) {
+		t.Fatalf("Expected %v but was %v", f, actual)
+	}
+}
+
+func TestFragmentJSONExplicitVersion(t *testing.T) {
+	f := splitV1(t)[0]
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(b), `"Version":1`) {
+		t.Fatalf("Expected an explicit version but was %s", b)
+	}
+}
+
+func TestFragmentJSONChecksumArray(t *testing.T) {
+	var f Fragment
+	err := json.Unmarshal([]byte(`{"ID":1,"Checksum":[1,2,3,4]}`), &f)
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestFragmentJSONUnknownVersion(t *testing.T) {
+	var f Fragment
+	err := json.Unmarshal([]byte(`{"Version":9}`), &f)
+
+	expected := "horcrux: unknown fragment version 9"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestAnswerJSON(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	b, err := json.Marshal(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []Answer
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, answers) {
+		t.Fatalf("Expected %v but was %v", answers, actual)
+	}
+
+	s, err := Recover(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
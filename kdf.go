@@ -0,0 +1,249 @@
+package horcrux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/codahale/chacha20"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KDFScrypt is the scrypt key derivation algorithm. Fragments with an
+	// empty KDF field use scrypt.
+	KDFScrypt = "scrypt"
+
+	// KDFBcrypt is the bcrypt key derivation algorithm. bcrypt only produces
+	// 192 bits of output, which is hashed with SHA-256 to produce a 256-bit
+	// key, so the effective entropy of the key is at most 192 bits. bcrypt also
+	// ignores all but the first 72 bytes of an answer.
+	KDFBcrypt = "bcrypt"
+
+	// KDFArgon2id is the Argon2id key derivation algorithm.
+	KDFArgon2id = "argon2id"
+
+	// KDFKey marks extra fragments which are encrypted directly with a key
+	// rather than one derived from an answer. See WithExtraShares.
+	KDFKey = "key"
+)
+
+// kdfVersions maps each key derivation algorithm to the versions of it which
+// this package knows how to use. An unversioned algorithm is always supported.
+var kdfVersions = map[string][]string{
+	KDFScrypt:   {"v1"},
+	KDFBcrypt:   {"v1"},
+	KDFArgon2id: {"v1"},
+	KDFKey:      {"v1"},
+}
+
+// ErrTimeout is returned when key derivation takes longer than the timeout
+// given by WithTimeout or WithSplitTimeout.
+var ErrTimeout = errors.New("horcrux: key derivation timed out")
+
+// AlgorithmVersionUnsupportedError is returned when a fragment's key derivation
+// algorithm is tagged with a version which this package does not support.
+type AlgorithmVersionUnsupportedError struct {
+	Algorithm string // Algorithm is the key derivation algorithm.
+	Version   string // Version is the unsupported version tag.
+}
+
+func (e AlgorithmVersionUnsupportedError) Error() string {
+	return fmt.Sprintf("horcrux: unsupported %s version %q",
+		e.Algorithm, e.Version)
+}
+
+// WithAlgorithmVersion returns a copy of the fragment with its key derivation
+// algorithm tagged with the given version (e.g. "scrypt/v1").
+func (f Fragment) WithAlgorithmVersion(v string) Fragment {
+	alg, _ := parseKDF(f.KDF)
+	f.KDF = alg + "/" + v
+	if f.StructureChecksum != nil {
+		f.StructureChecksum = f.ComputeStructureChecksum()
+	}
+	return f
+}
+
+// parseKDF splits a KDF field into its algorithm name and version tag.
+func parseKDF(s string) (alg, version string) {
+	alg, version, _ = strings.Cut(s, "/")
+	if alg == "" {
+		alg = KDFScrypt
+	}
+	return alg, version
+}
+
+// ErrKDFError is returned when a KDF, whether built-in or custom, fails to
+// derive a key, e.g. because of bad parameters, including when Split rejects
+// the parameters before deriving any keys.
+type ErrKDFError struct {
+	Cause error // Cause is the error returned by the KDF.
+}
+
+func (e ErrKDFError) Error() string {
+	return "horcrux: key derivation failed: " +
+		strings.TrimPrefix(e.Cause.Error(), "horcrux: ")
+}
+
+// Unwrap returns the error returned by the KDF.
+func (e ErrKDFError) Unwrap() error {
+	return e.Cause
+}
+
+// ErrAuthenticationFailed is returned when a fragment's share cannot be
+// decrypted, usually because the answer was wrong.
+type ErrAuthenticationFailed struct {
+	ID    int   // ID is the ID of the fragment.
+	Cause error // Cause is the error returned by the decryption backend.
+}
+
+func (e ErrAuthenticationFailed) Error() string {
+	return fmt.Sprintf("horcrux: fragment %d: %v", e.ID, e.Cause)
+}
+
+// Unwrap returns the error returned by the decryption backend.
+func (e ErrAuthenticationFailed) Unwrap() error {
+	return e.Cause
+}
+
+// ErrMixedKDF is returned by Recover in strict mode when the answers' fragments
+// do not all use the same key derivation algorithm.
+type ErrMixedKDF struct {
+	IDs []int // IDs are the IDs of the fragments which differ from the first.
+}
+
+func (e ErrMixedKDF) Error() string {
+	return fmt.Sprintf("horcrux: fragments %v use a different KDF", e.IDs)
+}
+
+// checkKDFs returns ErrMixedKDF if any of the answers' fragments use a
+// different key derivation algorithm than the first.
+func checkKDFs(answers []Answer) error {
+	if len(answers) == 0 {
+		return nil
+	}
+
+	var ids []int
+	first, _ := parseKDF(answers[0].KDF)
+	for _, a := range answers[1:] {
+		if alg, _ := parseKDF(a.KDF); alg != first {
+			ids = append(ids, int(a.ID))
+		}
+	}
+
+	if ids != nil {
+		return ErrMixedKDF{IDs: ids}
+	}
+	return nil
+}
+
+// derive is the function used to derive keys, which tests replace to observe
+// key derivations.
+var derive = deriveKey
+
+// deriveKey derives the fragment's encryption key from the given answer using
+// the fragment's key derivation algorithm and parameters.
+func deriveKey(f Fragment, answer string) ([]byte, error) {
+	alg, version := parseKDF(f.KDF)
+
+	versions, ok := kdfVersions[alg]
+	if !ok {
+		return customKey(f, alg, answer)
+	}
+
+	if version != "" {
+		supported := false
+		for _, v := range versions {
+			if v == version {
+				supported = true
+				break
+			}
+		}
+
+		if !supported {
+			return nil, AlgorithmVersionUnsupportedError{
+				Algorithm: alg,
+				Version:   version,
+			}
+		}
+	}
+
+	var k []byte
+	var err error
+	switch alg {
+	case KDFBcrypt:
+		k, err = bcryptKey([]byte(answer), f.Salt, f.BcryptCost)
+	case KDFArgon2id:
+		k, err = argon2Key([]byte(answer), f.Salt, f.Argon2Time, f.Argon2Memory,
+			f.Argon2Threads)
+	case KDFKey:
+		if len(answer) != chacha20.KeySize {
+			err = fmt.Errorf("horcrux: extra share key must be %d bytes",
+				chacha20.KeySize)
+		} else {
+			k = []byte(answer)
+		}
+	default:
+		k, err = scrypt.Key([]byte(answer), f.Salt, f.N, f.R, f.P, chacha20.KeySize)
+	}
+
+	if err != nil {
+		return nil, ErrKDFError{Cause: err}
+	}
+	return k, nil
+}
+
+// withTimeout returns a child of the given context, or of the background
+// context if it is nil, which expires after the given duration, or which never
+// expires on its own if the duration is zero.
+func withTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	if d > 0 {
+		return context.WithTimeout(parent, d)
+	}
+	return context.WithCancel(parent)
+}
+
+// deriveKeyContext derives the fragment's key like deriveKey, but returns early
+// if the context is done first. Key derivation cannot be interrupted, so it
+// continues in the background until it finishes.
+func deriveKeyContext(ctx context.Context, f Fragment, answer string) ([]byte, error) {
+	if err := ctxErr(ctx); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		key []byte
+		err error
+	}
+
+	c := make(chan result, 1)
+	go func() {
+		k, err := derive(f, answer)
+		c <- result{k, err}
+	}()
+
+	select {
+	case r := <-c:
+		return r.key, r.err
+	case <-ctx.Done():
+		// zero the key once the abandoned derivation finishes
+		go func() { zero((<-c).key) }()
+		return nil, ctxErr(ctx)
+	}
+}
+
+// ctxErr returns the context's error, translating an expired deadline into
+// ErrTimeout.
+func ctxErr(ctx context.Context) error {
+	err := ctx.Err()
+	if err == context.DeadlineExceeded {
+		return ErrTimeout
+	}
+	return err
+}
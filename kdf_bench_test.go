@@ -0,0 +1,91 @@
+package horcrux
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const kdfBudget = 100 * time.Millisecond
+
+// kdfCandidate is a key derivation function whose cost can be scaled.
+type kdfCandidate struct {
+	name   string
+	derive func(cost int) // derive derives a key at the given cost.
+	memory func(cost int) int
+	bits   func(cost int) float64 // bits is log2 of the work per guess.
+}
+
+var kdfCandidates = []kdfCandidate{
+	{
+		name: "scrypt",
+		derive: func(cost int) {
+			_, _ = scrypt.Key([]byte("answer"), make([]byte, saltLen), cost, 8, 1, 32)
+		},
+		memory: func(cost int) int { return 128 * 8 * cost },
+		bits:   func(cost int) float64 { return math.Log2(float64(cost)) },
+	},
+	{
+		name: "argon2id",
+		derive: func(cost int) {
+			_ = argon2IDKey([]byte("answer"), make([]byte, saltLen), uint32(cost),
+				64*1024, 1, 32)
+		},
+		memory: func(cost int) int { return 64 * 1024 * 1024 },
+		bits:   func(cost int) float64 { return math.Log2(float64(cost) * 64 * 1024) },
+	},
+	{
+		name: "pbkdf2-sha512",
+		derive: func(cost int) {
+			_ = pbkdf2.Key([]byte("answer"), make([]byte, saltLen), cost, 32, sha512.New)
+		},
+		memory: func(cost int) int { return 0 },
+		bits:   func(cost int) float64 { return math.Log2(float64(cost)) },
+	},
+}
+
+// calibrate doubles the cost until a derivation takes at least the budget.
+func (c kdfCandidate) calibrate(budget time.Duration) int {
+	for cost := 2; ; cost *= 2 {
+		start := time.Now()
+		c.derive(cost)
+		if time.Since(start) >= budget || cost >= 1<<30 {
+			return cost
+		}
+	}
+}
+
+func BenchmarkKDFComparison(b *testing.B) {
+	var table strings.Builder
+	fmt.Fprintf(&table, "\n%-14s %12s %12s %10s %12s\n",
+		"KDF", "cost", "memory", "bits", "bits/s")
+
+	for _, c := range kdfCandidates {
+		c := c
+		cost := c.calibrate(kdfBudget)
+		bits := c.bits(cost)
+
+		var perOp time.Duration
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.derive(cost)
+			}
+			perOp = b.Elapsed() / time.Duration(b.N)
+
+			b.ReportMetric(float64(c.memory(cost)), "mem-B/op")
+			b.ReportMetric(bits, "bits")
+			b.ReportMetric(bits/perOp.Seconds(), "bits/s")
+		})
+
+		fmt.Fprintf(&table, "%-14s %12d %12d %10.1f %12.1f\n",
+			c.name, cost, c.memory(cost), bits, bits/perOp.Seconds())
+	}
+
+	b.Log(table.String())
+}
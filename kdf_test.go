@@ -0,0 +1,258 @@
+package horcrux
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithAlgorithmVersion(t *testing.T) {
+	f := Fragment{KDF: "scrypt/v0"}.WithAlgorithmVersion("v1")
+
+	expected := "scrypt/v1"
+	actual := f.KDF
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestWithAlgorithmVersionDefault(t *testing.T) {
+	f := Fragment{}.WithAlgorithmVersion("v1")
+
+	expected := "scrypt/v1"
+	actual := f.KDF
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestRecoverVersionedAlgorithm(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = Answer{
+			Fragment: frags[i].WithAlgorithmVersion("v1"),
+			Answer:   questions[frags[i].Question],
+		}
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestRecoverUnsupportedAlgorithmVersion(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = Answer{
+			Fragment: frags[i].WithAlgorithmVersion("v99"),
+			Answer:   questions[frags[i].Question],
+		}
+	}
+
+	s, err := Recover(answers)
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	expected := AlgorithmVersionUnsupportedError{
+		Algorithm: "scrypt",
+		Version:   "v99",
+	}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestSplitTimeout(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<16, 8, 1,
+		WithSplitTimeout(time.Millisecond))
+	if err != ErrTimeout {
+		t.Fatalf("Expected %v but was %v (%v)", ErrTimeout, err, frags)
+	}
+}
+
+func TestRecoverTimeout(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		f := frags[i]
+		f.N = 2 << 16
+		f.StructureChecksum = f.ComputeStructureChecksum()
+		answers[i] = Answer{
+			Fragment: f,
+			Answer:   questions[f.Question],
+		}
+	}
+
+	s, err := Recover(answers, WithTimeout(time.Millisecond))
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	if err != ErrTimeout {
+		t.Fatalf("Expected %v but was %v", ErrTimeout, err)
+	}
+}
+
+// mixedKDFAnswers returns two answers for a single split, one for an scrypt
+// fragment and one for a bcrypt fragment.
+func mixedKDFAnswers(t *testing.T) []Answer {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := frags[0].WithAnswer(questions[frags[0].Question])
+	b := frags[1].WithAnswer(questions[frags[1].Question])
+
+	shares, err := DecryptShares([]Answer{b})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// re-encrypting a share doesn't change which split it belongs to
+	bf, err := EncryptShares(shares, map[string]string{b.Question: b.Answer}, 2,
+		WithBcrypt(MinBcryptCost), func(c *splitConfig) { c.setID = a.SetID })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return []Answer{a, bf[0].WithAnswer(b.Answer)}
+}
+
+func TestRecoverMixedKDF(t *testing.T) {
+	actual, err := Recover(mixedKDFAnswers(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestRecoverMixedKDFStrict(t *testing.T) {
+	answers := mixedKDFAnswers(t)
+
+	_, err := Recover(answers, WithStrictKDF())
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	e, ok := err.(ErrMixedKDF)
+	if !ok {
+		t.Fatalf("Expected ErrMixedKDF but was %v", err)
+	}
+
+	expected := []int{int(answers[1].ID)}
+	if !reflect.DeepEqual(e.IDs, expected) {
+		t.Fatalf("Expected %v but was %v", expected, e.IDs)
+	}
+}
+
+func TestErrAuthenticationFailed(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer("wrong"),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	_, err = Recover(answers)
+
+	var auth ErrAuthenticationFailed
+	if !errors.As(err, &auth) {
+		t.Fatalf("Expected ErrAuthenticationFailed but was %v", err)
+	}
+
+	if auth.ID != int(frags[0].ID) {
+		t.Errorf("Expected %v but was %v", frags[0].ID, auth.ID)
+	}
+
+	var kdf ErrKDFError
+	if errors.As(err, &kdf) {
+		t.Fatalf("Expected no ErrKDFError but was %v", kdf)
+	}
+}
+
+func TestErrKDFError(t *testing.T) {
+	_, err := Split(secret, questions, 2, 0, 8, 1)
+
+	var kdf ErrKDFError
+	if !errors.As(err, &kdf) {
+		t.Fatalf("Expected ErrKDFError but was %v", err)
+	}
+
+	if kdf.Unwrap() == nil {
+		t.Fatal("Expected a cause but was nil")
+	}
+
+	var auth ErrAuthenticationFailed
+	if errors.As(err, &auth) {
+		t.Fatalf("Expected no ErrAuthenticationFailed but was %v", auth)
+	}
+}
+
+func TestErrKDFErrorEveryKDF(t *testing.T) {
+	salt := make([]byte, 32)
+	for name, f := range map[string]Fragment{
+		"bcrypt":         {KDF: KDFBcrypt, BcryptCost: 5, Salt: salt},
+		"argon2id":       {KDF: KDFArgon2id, Salt: salt},
+		"key":            {KDF: KDFKey, Salt: salt},
+		"custom params":  {KDF: "test-hmac", KDFParams: []byte{0}, Salt: salt},
+		"custom key len": {KDF: "test-short", Salt: salt},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := deriveKey(f, "answer")
+
+			var kdf ErrKDFError
+			if !errors.As(err, &kdf) {
+				t.Fatalf("Expected ErrKDFError but was %v", err)
+			}
+		})
+	}
+}
+
+func TestErrKDFErrorOnRecover(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.N = 0
+	f.StructureChecksum = f.ComputeStructureChecksum()
+	_, err = Recover([]Answer{
+		f.WithAnswer(questions[f.Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	})
+
+	var kdf ErrKDFError
+	if !errors.As(err, &kdf) {
+		t.Fatalf("Expected ErrKDFError but was %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package horcrux
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// ErrAnswerTooShort is returned by Split when an answer is shorter than the
+// minimum set with WithMinAnswerLength.
+type ErrAnswerTooShort struct {
+	Question string // Question is the question with the short answer.
+	Length   int    // Length is the length of the answer, in characters.
+	Min      int    // Min is the minimum length.
+}
+
+func (e ErrAnswerTooShort) Error() string {
+	return fmt.Sprintf("horcrux: answer to %q is %d characters, minimum is %d",
+		e.Question, e.Length, e.Min)
+}
+
+// checkAnswerLengths returns ErrAnswerTooShort for the first question, in
+// order, whose normalized answer is shorter than min characters.
+func checkAnswerLengths(questions map[string]string, normalize NormalizeFunc, min int) error {
+	if min <= 0 {
+		return nil
+	}
+
+	qs := make([]string, 0, len(questions))
+	for q := range questions {
+		qs = append(qs, q)
+	}
+	sort.Strings(qs)
+
+	for _, q := range qs {
+		a := questions[q]
+		if normalize != nil {
+			a = normalize(a)
+		}
+
+		if n := utf8.RuneCountInString(a); n < min {
+			return ErrAnswerTooShort{Question: q, Length: n, Min: min}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package horcrux
+
+import "testing"
+
+func TestSplitMinAnswerLength(t *testing.T) {
+	derived := false
+	old := derive
+	defer func() { derive = old }()
+	derive = func(f Fragment, answer string) ([]byte, error) {
+		derived = true
+		return old(f, answer)
+	}
+
+	q := map[string]string{
+		"What's your favorite color?": "red",
+		"What's your real name?":      "Rumplestiltskin",
+	}
+
+	_, err := Split(secret, q, 2, 2<<10, 8, 1, WithMinAnswerLength(8))
+
+	expected := ErrAnswerTooShort{
+		Question: "What's your favorite color?",
+		Length:   3,
+		Min:      8,
+	}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+
+	if derived {
+		t.Fatal("Expected no key derivation but one was attempted")
+	}
+}
+
+func TestSplitMinAnswerLengthNormalized(t *testing.T) {
+	q := map[string]string{
+		"What's your favorite color?": "  blue  ",
+		"What's your real name?":      "Rumplestiltskin",
+	}
+
+	_, err := Split(secret, q, 2, 2<<10, 8, 1,
+		WithMinAnswerLength(5), WithNamedNormalizer("trim+lower"))
+	if e, ok := err.(ErrAnswerTooShort); !ok || e.Length != 4 {
+		t.Fatalf("Expected a 4-character answer error but was %v", err)
+	}
+}
+
+func TestSplitMinAnswerLengthOK(t *testing.T) {
+	_, err := Split(secret, questions, 2, 2<<10, 8, 1, WithMinAnswerLength(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,140 @@
+package horcrux
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// migratedSuffix is the suffix of the files MigrateFragmentDir writes.
+const migratedSuffix = ".v2.pem"
+
+// MigrateFragmentDir upgrades the version 1 PEM fragment files (*.pem) in the
+// given directory. Fragments are grouped by K, and each group's secret is
+// recovered using the given answers, keyed by question, then split again with
+// the given options. The new fragment for each file "name.pem" is written to
+// "name.v2.pem"; existing files are never overwritten. Files which are already
+// version 2, or which have already been migrated, are skipped, so it is safe
+// to run MigrateFragmentDir more than once. Returns the number of fragments
+// migrated and the number which could not be, either because they could not
+// be read, had no answer, or their group could not be recovered.
+func MigrateFragmentDir(dir string, answers map[string]string, newOpts ...SplitOption) (migrated, failed int, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	groups := make(map[int][]fragmentFile)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != ".pem" ||
+			strings.HasSuffix(name, migratedSuffix) {
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, migratedName(name))); err == nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return migrated, failed, err
+		}
+
+		f, err := UnmarshalFragmentPEM(data)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		if f.version() == FragmentVersion2 {
+			continue
+		}
+
+		groups[f.K] = append(groups[f.K], fragmentFile{name, f})
+	}
+
+	ks := make([]int, 0, len(groups))
+	for k := range groups {
+		ks = append(ks, k)
+	}
+	sort.Ints(ks)
+
+	for _, k := range ks {
+		m, err := migrateGroup(dir, groups[k], k, answers, newOpts)
+		if err != nil {
+			return migrated, failed, err
+		}
+		migrated += m
+		failed += len(groups[k]) - m
+	}
+	return migrated, failed, nil
+}
+
+// fragmentFile is a fragment and the name of the file it was read from.
+type fragmentFile struct {
+	name string
+	f    Fragment
+}
+
+// migrateGroup recovers the secret shared by the given fragments and splits it
+// again, writing the new fragments alongside the old ones. Returns the number
+// of fragments written, or an error if one could not be written.
+func migrateGroup(dir string, files []fragmentFile, k int, answers map[string]string, opts []SplitOption) (int, error) {
+	var as []Answer
+	questions := make(map[string]string)
+	names := make(map[string][]string)
+	for _, ff := range files {
+		f := ff.f
+		if a, ok := answers[f.Question]; ok {
+			names[f.Question] = append(names[f.Question], ff.name)
+			as = append(as, f.WithAnswer(a))
+			questions[f.Question] = a
+			if f.AnswerHint != "" {
+				opts = append([]SplitOption{WithAnswerHint(f.Question, f.AnswerHint)}, opts...)
+			}
+		}
+	}
+
+	s, err := Recover(as)
+	if err != nil {
+		return 0, nil
+	}
+	defer zero(s)
+
+	res, err := SplitWithResult(s, questions, k, opts...)
+	if err != nil {
+		return 0, nil
+	}
+
+	n := 0
+	for _, f := range res.Fragments {
+		for _, name := range names[f.Question] {
+			if err := writeNewFragment(filepath.Join(dir, migratedName(name)), f); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// writeNewFragment writes the fragment to a new PEM file.
+func writeNewFragment(path string, f Fragment) error {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	if err := f.WritePEM(out); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// migratedName returns the name of the migrated file for the given file.
+func migratedName(name string) string {
+	return strings.TrimSuffix(name, ".pem") + migratedSuffix
+}
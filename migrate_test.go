@@ -0,0 +1,93 @@
+package horcrux
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeV1Dir writes version 1 fragments to PEM files in a new directory.
+func writeV1Dir(t *testing.T) string {
+	dir := t.TempDir()
+
+	for _, f := range splitV1(t) {
+		b, err := f.MarshalPEM()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := filepath.Join(dir, fmt.Sprintf("fragment-%d.pem", f.ID))
+		if err := os.WriteFile(name, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMigrateFragmentDir(t *testing.T) {
+	dir := writeV1Dir(t)
+
+	migrated, failed, err := MigrateFragmentDir(dir, questions,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if migrated != len(questions) || failed != 0 {
+		t.Fatalf("Expected %d/0 but was %d/%d", len(questions), migrated, failed)
+	}
+
+	var answers []Answer
+	for i := 1; i <= 2; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("fragment-%d.v2.pem", i))
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := UnmarshalFragmentPEM(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if f.Version != FragmentVersion2 {
+			t.Fatalf("Expected %v but was %v", FragmentVersion2, f.Version)
+		}
+
+		answers = append(answers, f.WithAnswer(questions[f.Question]))
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+
+	migrated, failed, err = MigrateFragmentDir(dir, questions,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if migrated != 0 || failed != 0 {
+		t.Fatalf("Expected 0/0 but was %d/%d", migrated, failed)
+	}
+}
+
+func TestMigrateFragmentDirMissingAnswers(t *testing.T) {
+	dir := writeV1Dir(t)
+
+	migrated, failed, err := MigrateFragmentDir(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if migrated != 0 || failed != len(questions) {
+		t.Fatalf("Expected 0/%d but was %d/%d", len(questions), migrated, failed)
+	}
+}
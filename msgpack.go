@@ -0,0 +1,21 @@
+package horcrux
+
+import "github.com/vmihailenco/msgpack/v5"
+
+// msgpackFragment has a Fragment's fields but not its methods, so that it is
+// encoded as a MessagePack map of its fields rather than as a binary value
+// holding its binary encoding.
+type msgpackFragment Fragment
+
+// ToMessagePack returns the fragment's MessagePack encoding: a map from field
+// names to values, with byte slices encoded as binary values.
+func (f Fragment) ToMessagePack() ([]byte, error) {
+	return msgpack.Marshal(msgpackFragment(f))
+}
+
+// FragmentFromMessagePack decodes a fragment from its MessagePack encoding.
+func FragmentFromMessagePack(data []byte) (Fragment, error) {
+	var f msgpackFragment
+	err := msgpack.Unmarshal(data, &f)
+	return Fragment(f), err
+}
@@ -0,0 +1,66 @@
+package horcrux
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestFragmentMessagePack(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := frags[0].ToMessagePack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := FragmentFromMessagePack(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, frags[0]) {
+		t.Fatalf("Expected %v but was %v", frags[0], actual)
+	}
+
+	j, err := json.Marshal(frags[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b) >= len(j) {
+		t.Fatalf("Expected MessagePack (%d bytes) to be smaller than JSON (%d bytes)",
+			len(b), len(j))
+	}
+}
+
+func TestFragmentMessagePackMap(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := frags[0].ToMessagePack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m map[string]interface{}
+	if err := msgpack.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := m["Question"]; v != frags[0].Question {
+		t.Fatalf("Expected %v but was %v", frags[0].Question, v)
+	}
+
+	if v, ok := m["Value"].([]byte); !ok || !bytes.Equal(v, frags[0].Value) {
+		t.Fatalf("Expected %v but was %v", frags[0].Value, m["Value"])
+	}
+}
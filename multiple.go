@@ -0,0 +1,206 @@
+package horcrux
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SplitMultiple splits each of the given secrets into its own set of encrypted
+// fragments using the same security questions, so that the same k answers will
+// recover all of them. Each secret's fragments are encrypted with their own
+// salts and nonces. Unless overridden with WithScryptParams, the recommended
+// scrypt parameters are used. Since only fragments are returned, WithExtraShares
+// isn't supported. Returns either a map of secret names to fragments or an
+// error.
+func SplitMultiple(secrets map[string][]byte, questions map[string]string, k int, opts ...SplitOption) (map[string][]Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.extraShares > 0 {
+		return nil, errors.New("horcrux: extra shares can't be used with SplitMultiple")
+	}
+
+	sets := make(map[string][]Fragment, len(secrets))
+	for name, secret := range secrets {
+		res, err := split(secret, questions, k, &cfg)
+		if err != nil {
+			return nil, err
+		}
+		sets[name] = res.Fragments
+	}
+
+	return sets, nil
+}
+
+// RecoverMultiple recovers the named secrets from their fragment sets using the
+// given answers. Answers are matched to each set's fragments by question, or by
+// its hash if the set was split with WithPrivateQuestions, so an answer to a
+// fragment from any one set can be used for all of them. Each set's own hints
+// and normalizers are used. Returns either a map of secret names to secrets or
+// an error.
+func RecoverMultiple(secretKeys []string, answers []Answer, fragmentSets map[string][]Fragment, opts ...RecoverOption) (map[string][]byte, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	secrets := make(map[string][]byte, len(secretKeys))
+	for _, name := range secretKeys {
+		frags, ok := fragmentSets[name]
+		if !ok {
+			return nil, fmt.Errorf("horcrux: no fragments for secret %q", name)
+		}
+
+		byQuestion := make(map[string]Fragment, len(frags))
+		for _, f := range frags {
+			byQuestion[questionKey(f)] = f
+		}
+
+		set := make([]Answer, 0, len(answers))
+		for _, a := range answers {
+			// answered fragments hold their plaintext questions
+			f, ok := byQuestion[hex.EncodeToString(hashQuestion(a.Question))]
+			if !ok {
+				return nil, fmt.Errorf(
+					"horcrux: no fragment for question %q in secret %q",
+					a.Question, name)
+			}
+			if f.QuestionIsHashed {
+				f = f.WithQuestion(a.Question)
+			}
+			set = append(set, Answer{Fragment: f, Answer: a.Answer})
+		}
+
+		s, err := recoverSecret(set, &cfg)
+		if err != nil {
+			return nil, err
+		}
+		secrets[name] = s
+	}
+
+	return secrets, nil
+}
+
+// questionKey returns the hex-encoded hash of the unanswered fragment's
+// question, which is the same whether or not the question is hashed in the
+// fragment.
+func questionKey(f Fragment) string {
+	if f.QuestionIsHashed {
+		return f.Question
+	}
+	return hex.EncodeToString(hashQuestion(f.Question))
+}
+
+// SplitLabeled splits the given labeled secrets, e.g. a password manager's
+// master password and a PGP key, into one set of encrypted fragments, so that
+// each question's holder keeps a single fragment for all of them. The secrets
+// are packed into one value before splitting, so they share each fragment's
+// salt and derived key. Unless overridden with WithScryptParams, the recommended
+// scrypt parameters are used. Returns either the fragments or an error.
+func SplitLabeled(secrets map[string][]byte, questions map[string]string, k int, opts ...SplitOption) ([]Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if len(secrets) == 0 {
+		return nil, errors.New("horcrux: no secrets to split")
+	}
+
+	packed := packLabeled(secrets)
+	defer zero(packed)
+
+	res, err := split(packed, questions, k, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return res.Fragments, nil
+}
+
+// RecoverLabeled recovers the labeled secrets split with SplitLabeled using
+// the given answers. Returns either a map of labels to secrets or an error.
+func RecoverLabeled(answers []Answer, opts ...RecoverOption) (map[string][]byte, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	packed, err := recoverSecret(answers, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(packed)
+
+	return unpackLabeled(packed)
+}
+
+// errNotLabeled is returned by RecoverLabeled when the recovered secret wasn't
+// split with SplitLabeled.
+var errNotLabeled = errors.New("horcrux: secret is not a set of labeled secrets")
+
+// packLabeled encodes the labeled secrets as a count followed by each label
+// and secret, in label order, each prefixed with its length.
+func packLabeled(secrets map[string][]byte) []byte {
+	labels := make([]string, 0, len(secrets))
+	for l := range secrets {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	var buf [binary.MaxVarintLen64]byte
+	b := append([]byte(nil), buf[:binary.PutUvarint(buf[:], uint64(len(labels)))]...)
+	for _, l := range labels {
+		b = append(b, buf[:binary.PutUvarint(buf[:], uint64(len(l)))]...)
+		b = append(b, l...)
+		b = append(b, buf[:binary.PutUvarint(buf[:], uint64(len(secrets[l])))]...)
+		b = append(b, secrets[l]...)
+	}
+	return b
+}
+
+// unpackLabeled decodes the labeled secrets encoded by packLabeled.
+func unpackLabeled(b []byte) (map[string][]byte, error) {
+	next := func() ([]byte, error) {
+		n, i := binary.Uvarint(b)
+		if i <= 0 || n > uint64(len(b)-i) {
+			return nil, errNotLabeled
+		}
+		v := b[i : i+int(n)]
+		b = b[i+int(n):]
+		return v, nil
+	}
+
+	count, i := binary.Uvarint(b)
+	if i <= 0 || count == 0 || count > uint64(len(b)) {
+		return nil, errNotLabeled
+	}
+	b = b[i:]
+
+	secrets := make(map[string][]byte, count)
+	for j := uint64(0); j < count; j++ {
+		l, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := next()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := secrets[string(l)]; ok {
+			return nil, errNotLabeled
+		}
+		secrets[string(l)] = append([]byte(nil), s...)
+	}
+
+	if len(b) != 0 {
+		return nil, errNotLabeled
+	}
+	return secrets, nil
+}
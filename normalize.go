@@ -0,0 +1,76 @@
+package horcrux
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultNormalizerID is the ID of the normalizer Split uses unless told
+// otherwise.
+const DefaultNormalizerID = "nfkc+fold+space"
+
+// A NormalizeFunc normalizes an answer before its key is derived, so that
+// answers which differ in unimportant ways produce the same key.
+type NormalizeFunc func(answer string) string
+
+// TrimLowerNormalizer removes leading and trailing whitespace from answers and
+// converts them to lower case. It is registered as "trim+lower".
+func TrimLowerNormalizer(answer string) string {
+	return strings.ToLower(strings.TrimSpace(answer))
+}
+
+// FoldNormalizer applies Unicode NFKC normalization and case folding to
+// answers, removes leading and trailing whitespace, and collapses runs of
+// whitespace to a single space, so that "Spot " and "spot" produce the same
+// key. It is registered as "nfkc+fold+space".
+func FoldNormalizer(answer string) string {
+	s := norm.NFKC.String(cases.Fold().String(norm.NFKC.String(answer)))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// localize returns a normalizer which lower-cases answers using the case
+// mappings of the given BCP 47 language tag before normalizing them with fn, so
+// that, e.g., Turkish dotted and dotless i fold the way a Turkish speaker
+// expects. If fn is nil or the locale is empty, fn is returned as-is.
+func localize(fn NormalizeFunc, locale string) NormalizeFunc {
+	if fn == nil || locale == "" {
+		return fn
+	}
+
+	tag := language.Make(locale)
+	return func(answer string) string {
+		return fn(cases.Lower(tag).String(answer))
+	}
+}
+
+var (
+	normalizersMu sync.RWMutex
+	normalizers   = map[string]NormalizeFunc{
+		"trim+lower":        TrimLowerNormalizer,
+		DefaultNormalizerID: FoldNormalizer,
+	}
+)
+
+// RegisterNormalizer registers the given normalizer under the given ID,
+// replacing any normalizer already registered under that ID. Because the ID is
+// stored in fragments, a normalizer must never change once fragments have been
+// split with it.
+func RegisterNormalizer(id string, fn NormalizeFunc) {
+	normalizersMu.Lock()
+	defer normalizersMu.Unlock()
+
+	normalizers[id] = fn
+}
+
+// LookupNormalizer returns the normalizer registered under the given ID.
+func LookupNormalizer(id string) (NormalizeFunc, bool) {
+	normalizersMu.RLock()
+	defer normalizersMu.RUnlock()
+
+	fn, ok := normalizers[id]
+	return fn, ok
+}
@@ -0,0 +1,229 @@
+package horcrux
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestNamedNormalizer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithNamedNormalizer("trim+lower"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		if frags[i].NormalizerID != "trim+lower" {
+			t.Fatalf("Expected normalizer ID but was %q", frags[i].NormalizerID)
+		}
+
+		a := "  " + strings.ToUpper(questions[frags[i].Question]) + " "
+		answers[i] = frags[i].WithAnswer(a)
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestUnknownNamedNormalizer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithNamedNormalizer("nope"))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := `horcrux: unknown normalizer "nope"`
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestAnonymousNormalizer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithNormalizer(strings.ToUpper))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		if frags[i].NormalizerID != "" {
+			t.Fatalf("Expected no normalizer ID but was %q", frags[i].NormalizerID)
+		}
+
+		answers[i] = frags[i].WithAnswer(strings.ToUpper(questions[frags[i].Question]))
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestRecoverUnregisteredNormalizerWarning(t *testing.T) {
+	RegisterNormalizer("upper", strings.ToUpper)
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithNamedNormalizer("upper"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	normalizersMu.Lock()
+	delete(normalizers, "upper")
+	normalizersMu.Unlock()
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(strings.ToUpper(questions[frags[i].Question]))
+	}
+
+	var buf bytes.Buffer
+	s, err := Recover(answers, WithLogger(log.New(&buf, "", 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+
+	if !strings.Contains(buf.String(), `unregistered normalizer "upper"`) {
+		t.Fatalf("Expected a warning but was %q", buf.String())
+	}
+}
+
+func TestLookupNormalizer(t *testing.T) {
+	fn, ok := LookupNormalizer("trim+lower")
+	if !ok {
+		t.Fatal("Expected trim+lower to be registered")
+	}
+
+	expected := "spot"
+	actual := fn(" Spot\n")
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+
+	if _, ok := LookupNormalizer("nope"); ok {
+		t.Fatal("Expected nope not to be registered")
+	}
+}
+
+func TestDefaultNormalizer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		if frags[i].NormalizerID != DefaultNormalizerID {
+			t.Fatalf("Expected %q but was %q", DefaultNormalizerID, frags[i].NormalizerID)
+		}
+
+		a := " " + strings.ToUpper(questions[frags[i].Question]) + "\t"
+		answers[i] = frags[i].WithAnswer(a)
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestDisableNormalizer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithNormalizer(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frags[0].NormalizerID != "" {
+		t.Fatalf("Expected no normalizer ID but was %q", frags[0].NormalizerID)
+	}
+
+	a := frags[0].WithAnswer(strings.ToUpper(questions[frags[0].Question]))
+	if _, err := openShare(context.Background(), a, &recoverConfig{}); err == nil {
+		t.Fatal("Expected an unnormalized answer to fail")
+	}
+}
+
+func TestFoldNormalizer(t *testing.T) {
+	for in, expected := range map[string]string{
+		"Spot ":            "spot",
+		"  big   RED\tdog": "big red dog",
+		"ｓｐｏｔ":             "spot",
+		"ﬁdo":              "fido",
+	} {
+		actual := FoldNormalizer(in)
+		if actual != expected {
+			t.Fatalf("Expected %q but was %q", expected, actual)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	q := map[string]string{
+		"Where were you born?":        "Istanbul",
+		"What's your favorite river?": "IRMAK",
+	}
+
+	frags, err := Split(secret, q, 2, 2<<10, 8, 1, WithLocale("tr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frags[0].Locale != "tr" {
+		t.Fatalf("Expected %q but was %q", "tr", frags[0].Locale)
+	}
+
+	answers := map[string]string{
+		"Where were you born?":        "ıstanbul",
+		"What's your favorite river?": "ırmak",
+	}
+
+	a := make([]Answer, len(frags))
+	for i, f := range frags {
+		a[i] = f.WithAnswer(answers[f.Question])
+	}
+
+	s, err := Recover(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestInvalidLocale(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithLocale("not a tag"))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := `horcrux: invalid locale "not a tag"`
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
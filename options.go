@@ -0,0 +1,537 @@
+package horcrux
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/codahale/chacha20"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// A SplitOption changes how Split encrypts fragments.
+type SplitOption func(*splitConfig)
+
+// A RecoverOption changes how Recover decrypts fragments.
+type RecoverOption func(*recoverConfig)
+
+type splitConfig struct {
+	kdf         string
+	n, r, p     int
+	bcryptCost  int
+	hints       map[string]string
+	commitments bool
+	escrowKey   []byte
+	timeout     time.Duration
+	ctx         context.Context
+	concurrency int
+
+	maxFragmentSize  int
+	privateQuestions bool
+	rand             io.Reader
+	normalize        NormalizeFunc
+	normalizerID     string
+	locale           string
+	alternates       map[string][]string
+	weights          map[string]int
+	hooks            *Hooks
+	answerSeparator  string
+	extraShares      int
+	extraShareKey    []byte
+	setID            []byte
+	group            int
+	groupThreshold   int
+	requiredQuestion string
+	minAnswerLength  int
+
+	minAnswerStrength float64
+
+	argon2Time, argon2Memory, argon2Threads int
+
+	customKDF KDF
+
+	aeadName string
+	aead     AEADFunc
+}
+
+type recoverConfig struct {
+	commitments   [][]byte
+	revocationKey ed25519.PublicKey
+	revoked       []Fragment
+	timeout       time.Duration
+	ctx           context.Context
+	concurrency   int
+	hmacKey       []byte
+	backend       DecryptionBackend
+	logger        *log.Logger
+	hooks         *Hooks
+	strictKDF     bool
+	kdfCache      KDFCache
+	typoBudget    int
+	aeads         map[string]AEADFunc
+}
+
+// logf logs a message to the configured logger, if any.
+func (c *recoverConfig) logf(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	}
+}
+
+// normalizer returns the answer normalizer for this split, if any.
+func (c *splitConfig) normalizer() (NormalizeFunc, error) {
+	if c.normalizerID != "" {
+		fn, ok := LookupNormalizer(c.normalizerID)
+		if !ok {
+			return nil, fmt.Errorf("horcrux: unknown normalizer %q",
+				c.normalizerID)
+		}
+		return localize(fn, c.locale), nil
+	}
+	return localize(c.normalize, c.locale), nil
+}
+
+// validate returns an error if the key derivation parameters for this split
+// are invalid, so that bad options are caught before any work is done.
+func (c *splitConfig) validate() error {
+	if c.locale != "" {
+		if _, err := language.Parse(c.locale); err != nil {
+			return fmt.Errorf("horcrux: invalid locale %q", c.locale)
+		}
+	}
+
+	for q, w := range c.weights {
+		if w < 1 {
+			return fmt.Errorf("horcrux: question %q has weight %d", q, w)
+		}
+	}
+
+	if err := c.validateKDFParams(); err != nil {
+		return ErrKDFError{Cause: err}
+	}
+
+	if alg, _ := parseKDF(c.kdf); kdfVersions[alg] == nil {
+		if _, ok := LookupKDF(c.kdf); !ok {
+			return fmt.Errorf("horcrux: unknown KDF %q", c.kdf)
+		}
+	}
+	return nil
+}
+
+// validateKDFParams returns an error if the KDF's parameters cannot be used to
+// derive keys.
+func (c *splitConfig) validateKDFParams() error {
+	switch alg, _ := parseKDF(c.kdf); alg {
+	case KDFScrypt:
+		p := ScryptParams{N: c.n, R: c.r, P: c.p, KeyLen: chacha20.KeySize}
+		return p.Validate()
+	case KDFBcrypt:
+		return checkBcryptCost(c.bcryptCost)
+	case KDFArgon2id:
+		return checkArgon2Params(c.argon2Time, c.argon2Memory, c.argon2Threads)
+	}
+	return nil
+}
+
+// getHooks returns the hooks set for this split, or the global hooks.
+func (c *splitConfig) getHooks() Hooks {
+	if c.hooks != nil {
+		return *c.hooks
+	}
+	return globalHooks()
+}
+
+// getHooks returns the hooks set for this recovery, or the global hooks.
+func (c *recoverConfig) getHooks() Hooks {
+	if c.hooks != nil {
+		return *c.hooks
+	}
+	return globalHooks()
+}
+
+// defaultSplitConfig returns a configuration which uses the default scrypt
+// parameters and the default normalizer.
+func defaultSplitConfig() splitConfig {
+	p := defaultScryptParams()
+	return splitConfig{kdf: KDFScrypt, n: p.N, r: p.R, p: p.P,
+		normalizerID: DefaultNormalizerID}
+}
+
+// random returns the source of randomness for salts and nonces.
+func (c *splitConfig) random() io.Reader {
+	if c.rand != nil {
+		return c.rand
+	}
+	return rand.Reader
+}
+
+// WithScryptParams sets the scrypt iteration (n), memory (r), and parallelism
+// (p) parameters used to derive each fragment's key.
+func WithScryptParams(n, r, p int) SplitOption {
+	return func(c *splitConfig) {
+		c.kdf = KDFScrypt
+		c.n, c.r, c.p = n, r, p
+		c.customKDF = nil
+	}
+}
+
+// WithBcrypt derives each fragment's key using bcrypt with the given cost
+// instead of scrypt. The cost must be at least 10.
+func WithBcrypt(cost int) SplitOption {
+	return func(c *splitConfig) {
+		c.kdf = KDFBcrypt
+		c.n, c.r, c.p = 0, 0, 0
+		c.bcryptCost = cost
+		c.customKDF = nil
+	}
+}
+
+// WithArgon2id derives each fragment's key using Argon2id with the given time,
+// memory (in KiB), and parallelism parameters instead of scrypt. Argon2id is
+// memory-hard, like scrypt, and its cost can be tuned with TuneArgon2id.
+func WithArgon2id(timeCost, memory, threads int) SplitOption {
+	return func(c *splitConfig) {
+		c.kdf = KDFArgon2id
+		c.n, c.r, c.p = 0, 0, 0
+		c.argon2Time, c.argon2Memory, c.argon2Threads = timeCost, memory, threads
+		c.customKDF = nil
+	}
+}
+
+// WithKDF derives each fragment's key using the given KDF instead of scrypt.
+// The KDF must be registered under its name with RegisterKDF, which Recover
+// uses to reconstruct it from the parameters stored in each fragment.
+func WithKDF(kdf KDF) SplitOption {
+	return func(c *splitConfig) {
+		c.kdf = kdf.Name()
+		c.n, c.r, c.p = 0, 0, 0
+		c.customKDF = kdf
+	}
+}
+
+// WithAnswerHint stores the given hint in plaintext alongside the fragment for
+// the given question. Hints are cosmetic and are ignored by Recover, so they
+// must not reveal the answer.
+func WithAnswerHint(question, hint string) SplitOption {
+	return func(c *splitConfig) {
+		if c.hints == nil {
+			c.hints = make(map[string]string)
+		}
+		c.hints[norm.NFC.String(question)] = hint
+	}
+}
+
+// WithShareCommitments makes SplitWithResult publish a hash commitment to each
+// share in SplitResult.Commitments. These are not Feldman commitments: they
+// detect modified shares, not inconsistent ones.
+func WithShareCommitments() SplitOption {
+	return func(c *splitConfig) {
+		c.commitments = true
+	}
+}
+
+// WithVerifyShareCommitments makes Recover check each decrypted share against the
+// given commitments before combining them.
+func WithVerifyShareCommitments(commitments [][]byte) RecoverOption {
+	return func(c *recoverConfig) {
+		c.commitments = commitments
+	}
+}
+
+// WithRevocationKey makes Recover verify the signatures of revoked fragments
+// with the given administrator public key.
+func WithRevocationKey(pub ed25519.PublicKey) RecoverOption {
+	return func(c *recoverConfig) {
+		c.revocationKey = pub
+	}
+}
+
+// WithRevocationList makes Recover refuse to use any of the given revoked
+// fragments, as returned by RevokeFragment and Revoke, even if the copy being
+// recovered with isn't marked as revoked. If WithRevocationKey is also given,
+// the revoked fragments' signatures must be valid.
+func WithRevocationList(revoked []Fragment) RecoverOption {
+	return func(c *recoverConfig) {
+		c.revoked = revoked
+	}
+}
+
+// WithAnswerEscrow encrypts each answer with the given 256-bit key using
+// AES-256-GCM and stores it in the fragment's EscrowedAnswer field. Anyone with
+// the escrow key can read every answer, and therefore recover the secret.
+func WithAnswerEscrow(escrowKey []byte) SplitOption {
+	return func(c *splitConfig) {
+		c.escrowKey = escrowKey
+	}
+}
+
+// WithSplitTimeout makes Split return ErrTimeout if deriving the fragments'
+// keys takes longer than the given duration.
+func WithSplitTimeout(d time.Duration) SplitOption {
+	return func(c *splitConfig) {
+		c.timeout = d
+	}
+}
+
+// WithTimeout makes Recover return ErrTimeout if deriving the answers' keys
+// takes longer than the given duration.
+func WithTimeout(d time.Duration) RecoverOption {
+	return func(c *recoverConfig) {
+		c.timeout = d
+	}
+}
+
+// WithMaxFragmentSize makes Split return ErrFragmentTooLarge if the binary
+// encoding of any fragment is larger than the given number of bytes.
+func WithMaxFragmentSize(bytes int) SplitOption {
+	return func(c *splitConfig) {
+		c.maxFragmentSize = bytes
+	}
+}
+
+// WithPrivateQuestions stores the SHA-256 hash of each question in its fragment
+// instead of the question itself. To recover the secret, each answer's
+// Question must be set back to the original question.
+func WithPrivateQuestions() SplitOption {
+	return func(c *splitConfig) {
+		c.privateQuestions = true
+	}
+}
+
+// WithFragmentHMACKey makes Recover verify each fragment's HMAC with the given
+// key before deriving its key.
+func WithFragmentHMACKey(key []byte) RecoverOption {
+	return func(c *recoverConfig) {
+		c.hmacKey = key
+	}
+}
+
+// WithDecryptionBackend makes Recover decrypt shares using the given backend
+// instead of DefaultDecryptionBackend.
+func WithDecryptionBackend(b DecryptionBackend) RecoverOption {
+	return func(c *recoverConfig) {
+		c.backend = b
+	}
+}
+
+// WithRandReader makes Split read salts and nonces from the given reader instead
+// of crypto/rand. This is only useful for producing repeatable fragments in
+// tests; a predictable reader makes fragments much easier to attack.
+func WithRandReader(r io.Reader) SplitOption {
+	return func(c *splitConfig) {
+		c.rand = r
+	}
+}
+
+// WithNormalizer makes Split normalize each answer with the given function
+// before deriving its key. Because the function is not recorded in the
+// fragments, answers must be normalized the same way before recovery. To have
+// Recover normalize answers automatically, use WithNamedNormalizer instead.
+// WithNormalizer(nil) disables normalization entirely.
+func WithNormalizer(fn NormalizeFunc) SplitOption {
+	return func(c *splitConfig) {
+		c.normalize = fn
+		c.normalizerID = ""
+	}
+}
+
+// WithNamedNormalizer makes Split normalize each answer with the normalizer
+// registered under the given ID, and records the ID in each fragment so that
+// Recover will normalize answers the same way.
+func WithNamedNormalizer(id string) SplitOption {
+	return func(c *splitConfig) {
+		c.normalize = nil
+		c.normalizerID = id
+	}
+}
+
+// WithLocale makes Split lower-case answers using the case mappings of the given
+// BCP 47 language tag, e.g. "tr" or "de", before normalizing them, and records
+// the tag in each fragment so that Recover folds answers the same way no matter
+// how the recovering machine is configured. It has no effect if normalization
+// is disabled.
+func WithLocale(tag string) SplitOption {
+	return func(c *splitConfig) {
+		c.locale = tag
+	}
+}
+
+// WithAlternateAnswers makes Split accept the given additional answers to each
+// question, e.g. "NYC" and "New York" as well as "New York City". The share is
+// encrypted once more under a key derived from each alternate answer, and
+// Recover succeeds with any of them. Each alternate answer costs a full key
+// derivation when splitting, and when recovering with a wrong answer.
+func WithAlternateAnswers(alternates map[string][]string) SplitOption {
+	return func(c *splitConfig) {
+		c.alternates = make(map[string][]string, len(alternates))
+		for q, answers := range alternates {
+			c.alternates[norm.NFC.String(q)] = answers
+		}
+	}
+}
+
+// WithWeights makes answers to some questions count more toward the threshold:
+// a question with a weight of 2 is given two shares, and its answer counts as
+// two of the K answers needed. Questions without a weight have a weight of 1.
+// The weighted shares are encrypted together in a single fragment.
+func WithWeights(weights map[string]int) SplitOption {
+	return func(c *splitConfig) {
+		c.weights = make(map[string]int, len(weights))
+		for q, w := range weights {
+			c.weights[norm.NFC.String(q)] = w
+		}
+	}
+}
+
+// WithRequiredQuestion makes the answer to the given question required to
+// recover the secret, along with K-1 of the other answers, e.g. so that the
+// owner of the secret is always one of the participants in its recovery. The
+// secret is split as with SplitGroups, with the required question as a group
+// of its own, and its fragment is marked as required. K must be at least 2.
+// Functions which can't split the secret into groups, such as SplitPooled,
+// EncryptShares, and AddFragment, return an error if given a required question.
+func WithRequiredQuestion(question string) SplitOption {
+	return func(c *splitConfig) {
+		c.requiredQuestion = norm.NFC.String(question)
+	}
+}
+
+// WithLogger makes Recover log warnings to the given logger.
+func WithLogger(l *log.Logger) RecoverOption {
+	return func(c *recoverConfig) {
+		c.logger = l
+	}
+}
+
+// WithCompoundAnswerSeparator records that the answers are compound answers
+// whose parts were joined with the given separator, so that NewCompoundAnswer
+// can join them the same way.
+func WithCompoundAnswerSeparator(sep string) SplitOption {
+	return func(c *splitConfig) {
+		c.answerSeparator = sep
+	}
+}
+
+// WithExtraShares makes SplitWithResult generate the given number of shares in
+// addition to those for the questions, e.g. for backups stored offline. The
+// extra fragments have no question, and are encrypted with the key given with
+// WithExtraShareKey.
+func WithExtraShares(extra int) SplitOption {
+	return func(c *splitConfig) {
+		c.extraShares = extra
+	}
+}
+
+// WithExtraShareKey sets the 256-bit key used to encrypt extra shares.
+func WithExtraShareKey(key []byte) SplitOption {
+	return func(c *splitConfig) {
+		c.extraShareKey = key
+	}
+}
+
+// WithMinAnswerLength makes Split return ErrAnswerTooShort if any answer is
+// shorter than the given number of characters once normalized.
+func WithMinAnswerLength(n int) SplitOption {
+	return func(c *splitConfig) {
+		c.minAnswerLength = n
+	}
+}
+
+// WithMinAnswerStrength makes Split return ErrAnswerTooWeak if the base-2
+// logarithm of the work needed to guess any answer is less than the given
+// number of bits. The work is the answer's estimated guesses, as estimated by
+// EstimateAnswerStrength, times the KDF's cost per guess, e.g. 2^18 for the
+// default scrypt parameters, so a costlier KDF allows weaker answers.
+func WithMinAnswerStrength(bits float64) SplitOption {
+	return func(c *splitConfig) {
+		c.minAnswerStrength = bits
+	}
+}
+
+// WithSplitHooks makes Split call the given hooks instead of those set with
+// SetHooks.
+func WithSplitHooks(h Hooks) SplitOption {
+	return func(c *splitConfig) {
+		c.hooks = &h
+	}
+}
+
+// WithHooks makes Recover call the given hooks instead of those set with
+// SetHooks.
+func WithHooks(h Hooks) RecoverOption {
+	return func(c *recoverConfig) {
+		c.hooks = &h
+	}
+}
+
+// WithSplitConcurrency makes Split derive up to n fragments' keys at once. By
+// default, GOMAXPROCS keys are derived at once, unless WithRandReader is used,
+// in which case keys are derived one at a time.
+func WithSplitConcurrency(n int) SplitOption {
+	return func(c *splitConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithConcurrency makes Recover derive up to n answers' keys at once. By
+// default, GOMAXPROCS keys are derived at once.
+func WithConcurrency(n int) RecoverOption {
+	return func(c *recoverConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithStrictKDF makes Recover return ErrMixedKDF if the answers' fragments do
+// not all use the same key derivation algorithm. By default, each fragment's
+// key is derived with its own algorithm.
+func WithStrictKDF() RecoverOption {
+	return func(c *recoverConfig) {
+		c.strictKDF = true
+	}
+}
+
+// WithKDFCache makes Recover look up derived keys in the given cache before
+// deriving them, and cache the keys of answers which decrypt their fragments.
+func WithKDFCache(cache KDFCache) RecoverOption {
+	return func(c *recoverConfig) {
+		c.kdfCache = cache
+	}
+}
+
+// WithSplitAEAD makes Split encrypt shares with AEADs returned by the given
+// function instead of ChaCha20Poly1305, and records the given name as each
+// fragment's cipher. Recover needs the same function, given to WithAEAD.
+func WithSplitAEAD(name string, fn AEADFunc) SplitOption {
+	return func(c *splitConfig) {
+		c.aeadName = name
+		c.aead = fn
+	}
+}
+
+// WithTypoTolerance makes Recover try up to budget variations of each wrong
+// answer before giving up: the answer without trailing punctuation, made
+// singular or plural, or with two adjacent characters swapped. Each variation
+// costs a full key derivation, so variations are derived concurrently.
+func WithTypoTolerance(budget int) RecoverOption {
+	return func(c *recoverConfig) {
+		c.typoBudget = budget
+	}
+}
+
+// WithAEAD makes Recover decrypt the shares of fragments whose cipher is the
+// given name with AEADs returned by the given function. It may be given more
+// than once, for fragments which use different ciphers. Such shares are not
+// decrypted by the decryption backend.
+func WithAEAD(name string, fn AEADFunc) RecoverOption {
+	return func(c *recoverConfig) {
+		if c.aeads == nil {
+			c.aeads = make(map[string]AEADFunc)
+		}
+		c.aeads[name] = fn
+	}
+}
@@ -0,0 +1,181 @@
+// Package paper lays out horcrux fragments as printable SVG pages, for holders
+// who will keep their fragments on paper. Each page has the fragment's
+// question, a QR code, the fragment typed out as text, and instructions for
+// recovering the secret.
+package paper
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"strings"
+
+	"github.com/codahale/horcrux"
+	"github.com/codahale/horcrux/qr"
+)
+
+const (
+	groupSize     = 4  // groupSize is the number of characters in a group.
+	groupsPerLine = 8  // groupsPerLine is the number of groups on a line.
+	wrapWidth     = 60 // wrapWidth is the width at which prose is wrapped.
+)
+
+var shareEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ShareText returns the fragment's binary encoding as base32 text, in groups of
+// four characters and lines of eight groups, for typing in by hand.
+func ShareText(f horcrux.Fragment) (string, error) {
+	b, err := f.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	s := shareEncoding.EncodeToString(b)
+
+	var lines []string
+	var groups []string
+	for len(s) > 0 {
+		n := groupSize
+		if n > len(s) {
+			n = len(s)
+		}
+		groups = append(groups, s[:n])
+		s = s[n:]
+
+		if len(groups) == groupsPerLine || len(s) == 0 {
+			lines = append(lines, strings.Join(groups, " "))
+			groups = nil
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ParseShareText decodes a fragment from text returned by ShareText. Case,
+// whitespace, and dashes are ignored, and the commonly confused digits 0, 1,
+// and 8 are read as the letters O, I, and B.
+func ParseShareText(s string) (horcrux.Fragment, error) {
+	var f horcrux.Fragment
+
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n', '-':
+			return -1
+		case '0':
+			return 'O'
+		case '1':
+			return 'I'
+		case '8':
+			return 'B'
+		}
+		return r
+	}, strings.ToUpper(s))
+
+	b, err := shareEncoding.DecodeString(s)
+	if err != nil {
+		return f, errors.New("horcrux: mistyped fragment text")
+	}
+
+	err = f.UnmarshalBinary(b)
+	return f, err
+}
+
+// Page lays out the fragment as a printable, US Letter-sized SVG page.
+func Page(f horcrux.Fragment) ([]byte, error) {
+	text, err := ShareText(f)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := qr.Encode(f, horcrux.QRCorrectionQuartile, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	question := f.Question
+	if f.QuestionIsHashed {
+		question = "(This question is private. Ask the person who gave you this page.)"
+	}
+
+	data := page{
+		ID:       int(f.ID),
+		K:        f.K,
+		Question: wrap(question),
+		Hint:     f.AnswerHint,
+		QRCode: template.URL("data:image/png;base64," +
+			base64.StdEncoding.EncodeToString(img)),
+		Text: strings.Split(text, "\n"),
+	}
+	data.Y = 760 + 22*len(data.Text)
+
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Pages lays out each fragment in the set as its own page.
+func Pages(fs horcrux.FragmentSet) ([][]byte, error) {
+	pages := make([][]byte, len(fs))
+	for i, f := range fs {
+		p, err := Page(f)
+		if err != nil {
+			return nil, err
+		}
+		pages[i] = p
+	}
+	return pages, nil
+}
+
+type page struct {
+	ID       int
+	K        int
+	Question []string
+	Hint     string
+	QRCode   template.URL
+	Text     []string
+	Y        int // Y is where the instructions start.
+}
+
+// wrap breaks s into lines of at most wrapWidth bytes, between words where
+// possible.
+func wrap(s string) []string {
+	var lines []string
+	line := ""
+	for _, w := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(w) > wrapWidth {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	return append(lines, line)
+}
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+	"mul": func(a, b int) int { return a * b },
+}).Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="8.5in" height="11in" viewBox="0 0 850 1100">
+<rect width="850" height="1100" fill="white"/>
+<g font-family="Helvetica, Arial, sans-serif" fill="black">
+<text x="75" y="100" font-size="28" font-weight="bold">Horcrux fragment #{{.ID}}</text>
+<text x="75" y="140" font-size="14">Keep this page somewhere safe. It is one piece of a secret; {{.K}} pieces are needed to recover it.</text>
+<text x="75" y="200" font-size="16" font-weight="bold">Your question</text>
+{{range $i, $l := .Question}}<text x="75" y="{{add 230 (mul $i 24)}}" font-size="18">{{$l}}</text>
+{{end}}{{if .Hint}}<text x="75" y="{{add 240 (mul (len .Question) 24)}}" font-size="14" font-style="italic">Hint: {{.Hint}}</text>
+{{end}}<image x="275" y="340" width="300" height="300" href="{{.QRCode}}"/>
+<text x="75" y="690" font-size="16" font-weight="bold">Fragment</text>
+{{range $i, $l := .Text}}<text x="75" y="{{add 720 (mul $i 22)}}" font-size="16" font-family="Courier, monospace">{{$l}}</text>
+{{end}}<text x="75" y="{{.Y}}" font-size="16" font-weight="bold">To recover the secret</text>
+<text x="75" y="{{add .Y 30}}" font-size="13">1. Scan the QR code, or type in the fragment above. Case, spaces, and dashes don't matter.</text>
+<text x="75" y="{{add .Y 52}}" font-size="13">2. Answer the question exactly as you did when this page was made.</text>
+<text x="75" y="{{add .Y 74}}" font-size="13">3. Do the same with at least {{.K}} fragments in total, then recover the secret with horcrux.</text>
+<text x="75" y="{{add .Y 96}}" font-size="13">Never share your answer with anyone who asks for it unexpectedly.</text>
+</g>
+</svg>
+`))
@@ -0,0 +1,108 @@
+package paper
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/codahale/horcrux"
+	"github.com/codahale/horcrux/testhelpers"
+)
+
+var questions = map[string]string{
+	"What's your first pet's name?":     "Spot",
+	"What's your least favorite food?":  "broccoli",
+	"What's your mother's maiden name?": "Hernandez",
+}
+
+func TestShareText(t *testing.T) {
+	frags := testhelpers.MustSplitFast(t, []byte("secret"), questions, 2)
+
+	s, err := ShareText(frags[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range strings.Split(s, "\n") {
+		if len(l) > groupsPerLine*(groupSize+1)-1 {
+			t.Fatalf("Line is too long: %q", l)
+		}
+	}
+
+	// typed in by hand, sloppily
+	typed := strings.ToLower(strings.ReplaceAll(s, " ", "-"))
+	typed = strings.ReplaceAll(typed, "o", "0")
+
+	actual, err := ParseShareText(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, frags[0]) {
+		t.Fatalf("Expected %v but was %v", frags[0], actual)
+	}
+}
+
+func TestParseShareTextMistyped(t *testing.T) {
+	f, err := ParseShareText("AAAA BBB!")
+	if err == nil {
+		t.Fatalf("Expected error but got %v", f)
+	}
+}
+
+func TestPages(t *testing.T) {
+	frags := testhelpers.MustSplitFast(t, []byte("secret"), questions, 2)
+	frags[0].AnswerHint = "<his name & yours>"
+
+	pages, err := Pages(horcrux.FragmentSet(frags))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pages) != len(frags) {
+		t.Fatalf("Expected %v pages but was %v", len(frags), len(pages))
+	}
+
+	for i, p := range pages {
+		text := svgText(t, p)
+
+		for _, s := range []string{frags[i].Question, "2 pieces are needed"} {
+			if !strings.Contains(text, s) {
+				t.Errorf("Expected page %d to contain %q", i, s)
+			}
+		}
+
+		if !bytes.Contains(p, []byte(`href="data:image/png;base64,`)) {
+			t.Errorf("Expected page %d to contain a QR code", i)
+		}
+	}
+
+	if !strings.Contains(svgText(t, pages[0]), "Hint: <his name & yours>") {
+		t.Error("Expected page 0 to contain the escaped hint")
+	}
+}
+
+// svgText checks that the SVG is well-formed and returns its text.
+func svgText(t *testing.T, svg []byte) string {
+	t.Helper()
+
+	var text strings.Builder
+	d := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if c, ok := tok.(xml.CharData); ok {
+			text.Write(c)
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,61 @@
+package horcrux
+
+import (
+	"runtime"
+	"sync"
+)
+
+// workers returns the number of keys to derive at once: n if it is positive,
+// or GOMAXPROCS otherwise.
+func workers(n int) int {
+	if n > 0 {
+		return n
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// parallel calls fn with each index below n, with up to limit calls running at
+// once. Once a call fails, calls which haven't started yet are skipped. It
+// returns the error from the lowest failing index, which is the same error a
+// sequential loop would return.
+func parallel(n, limit int, fn func(i int) error) error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+
+	var mu sync.Mutex
+	failed := false
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fn(i); err != nil {
+				errs[i] = err
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
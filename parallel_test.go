@@ -0,0 +1,87 @@
+package horcrux
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	var mu sync.Mutex
+	running, max := 0, 0
+
+	err := parallel(10, 3, func(i int) error {
+		mu.Lock()
+		running++
+		if running > max {
+			max = running
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if max > 3 {
+		t.Fatalf("Expected at most 3 at once but was %d", max)
+	}
+}
+
+func TestParallelFirstError(t *testing.T) {
+	err := parallel(10, 10, func(i int) error {
+		if i >= 4 {
+			// make later failures finish first
+			time.Sleep(time.Duration(10-i) * time.Millisecond)
+			return errors.New(string(rune('0' + i)))
+		}
+		return nil
+	})
+
+	if err == nil || err.Error() != "4" {
+		t.Fatalf("Expected %v but was %v", "4", err)
+	}
+}
+
+func TestSplitConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	ids := make(map[int]bool)
+	h := Hooks{
+		OnFragmentEncrypted: func(id int, elapsed time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			ids[id] = true
+		},
+	}
+
+	frags, err := SplitWithOptions(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1), WithSplitConcurrency(4), WithSplitHooks(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != len(questions) {
+		t.Fatalf("Expected %v but was %v", len(questions), len(ids))
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+
+	s, err := Recover(answers, WithConcurrency(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
@@ -0,0 +1,88 @@
+package horcrux
+
+import (
+	"errors"
+	"sort"
+)
+
+// PartialResult is the result of an attempt to recover a secret which may not
+// have had enough answers.
+type PartialResult struct {
+	// Secret is the recovered secret, or nil if more answers are needed.
+	Secret []byte
+
+	// DecryptedIDs are the IDs of the fragments whose shares were decrypted.
+	DecryptedIDs []int
+
+	// RemainingNeeded is the number of additional shares needed to recover the
+	// secret, i.e. the number of correct answers to unweighted fragments.
+	RemainingNeeded int
+}
+
+// Destroy zeroes and discards the recovered secret.
+func (r *PartialResult) Destroy() {
+	zero(r.Secret)
+	r.Secret = nil
+}
+
+// RecoverPartial combines the given answers like Recover, but does not treat
+// having fewer than K answers as an error. Instead, it returns a PartialResult
+// with a nil secret which records how many more answers are needed. An error
+// is still returned if any answer fails to decrypt its fragment.
+func RecoverPartial(answers []Answer, opts ...RecoverOption) (*PartialResult, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if len(answers) == 0 {
+		return nil, errors.New("horcrux: need at least 1 answer")
+	}
+
+	k := answers[0].K
+	h := cfg.getHooks()
+	h.recoverStart(len(answers), k)
+
+	if cfg.strictKDF {
+		if err := checkKDFs(answers); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkSets(answers); err != nil {
+		return nil, err
+	}
+
+	if err := checkUngrouped(answers); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	res := &PartialResult{}
+	shares := make([]share, 0, len(answers))
+	defer func() { wipeShares(shares) }()
+	for _, a := range answers {
+		s, err := openShare(ctx, a, &cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, s)
+		res.DecryptedIDs = append(res.DecryptedIDs, int(s.id))
+	}
+	sort.Ints(res.DecryptedIDs)
+
+	if have := sharesWeight(shares); have < k {
+		res.RemainingNeeded = k - have
+		return res, nil
+	}
+
+	secret, err := safeCombine(shares, k)
+	if err != nil {
+		return nil, err
+	}
+	res.Secret = secret
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package horcrux
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestRecoverPartial(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[1].WithAnswer(questions[frags[1].Question]),
+		frags[0].WithAnswer(questions[frags[0].Question]),
+	}
+
+	res, err := RecoverPartial(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Secret != nil {
+		t.Fatalf("Expected no secret but was %v", res.Secret)
+	}
+
+	if res.RemainingNeeded != 1 {
+		t.Errorf("Expected %v but was %v", 1, res.RemainingNeeded)
+	}
+
+	expected := []int{int(frags[0].ID), int(frags[1].ID)}
+	if !reflect.DeepEqual(res.DecryptedIDs, expected) {
+		t.Errorf("Expected %v but was %v", expected, res.DecryptedIDs)
+	}
+
+	answers = append(answers, frags[2].WithAnswer(questions[frags[2].Question]))
+
+	res, err = RecoverPartial(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(res.Secret, secret) {
+		t.Fatalf("Expected %v but was %v", secret, res.Secret)
+	}
+
+	if res.RemainingNeeded != 0 {
+		t.Errorf("Expected %v but was %v", 0, res.RemainingNeeded)
+	}
+}
+
+func TestRecoverPartialWrongAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer("wrong"),
+	}
+
+	res, err := RecoverPartial(answers)
+	if err == nil {
+		t.Fatalf("Expected error but got %v", res)
+	}
+}
+
+func TestRecoverPartialNoAnswers(t *testing.T) {
+	if _, err := RecoverPartial(nil); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
@@ -0,0 +1,92 @@
+package horcrux
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const pemType = "HORCRUX FRAGMENT"
+
+// MarshalPEM returns the fragment as a PEM block. The block's headers describe
+// the fragment for human readers; its contents are the fragment's JSON
+// encoding.
+func (f Fragment) MarshalPEM() ([]byte, error) {
+	b, err := f.pemBlock()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(b), nil
+}
+
+// WritePEM writes the fragment to w as a PEM block.
+func (f Fragment) WritePEM(w io.Writer) error {
+	b, err := f.pemBlock()
+	if err != nil {
+		return err
+	}
+	return pem.Encode(w, b)
+}
+
+// UnmarshalFragmentPEM decodes a fragment from the first PEM block in data.
+// Lines may be indented or quoted with '>', as they are when a PEM block is
+// pasted into or replied to in an email.
+func UnmarshalFragmentPEM(data []byte) (Fragment, error) {
+	var f Fragment
+
+	b, _ := pem.Decode(unquoteLines(data))
+	if b == nil {
+		return f, errors.New("horcrux: no PEM block found")
+	}
+
+	if b.Type != pemType {
+		return f, fmt.Errorf("horcrux: unexpected PEM block type %q", b.Type)
+	}
+
+	if err := json.Unmarshal(b.Bytes, &f); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// ReadFragmentPEM reads a PEM block from r and decodes it as a fragment.
+func ReadFragmentPEM(r io.Reader) (Fragment, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Fragment{}, err
+	}
+	return UnmarshalFragmentPEM(data)
+}
+
+func (f Fragment) pemBlock() (*pem.Block, error) {
+	j, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	h := map[string]string{
+		"ID":       strconv.Itoa(int(f.ID)),
+		"K":        strconv.Itoa(f.K),
+		"Question": strconv.Quote(f.Question),
+	}
+	if f.AnswerHint != "" {
+		h["Answer-Hint"] = strconv.Quote(f.AnswerHint)
+	}
+
+	return &pem.Block{Type: pemType, Headers: h, Bytes: j}, nil
+}
+
+// unquoteLines removes leading whitespace and email quote markers from each
+// line of data, and carriage returns from the end of each line.
+func unquoteLines(data []byte) []byte {
+	lines := bytes.Split(data, []byte("\n"))
+	for i, l := range lines {
+		l = bytes.TrimLeft(l, " \t>")
+		lines[i] = bytes.TrimRight(l, "\r")
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
@@ -0,0 +1,114 @@
+package horcrux
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFragmentPEM(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAnswerHint("What's your first pet's name?", "SP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		b, err := f.MarshalPEM()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(string(b), "-----BEGIN HORCRUX FRAGMENT-----\n") {
+			t.Fatalf("Unexpected PEM encoding: %s", b)
+		}
+
+		actual, err := UnmarshalFragmentPEM(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, f) {
+			t.Fatalf("Expected %v but was %v", f, actual)
+		}
+	}
+}
+
+func TestFragmentPEMPipe(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		err := frags[0].WritePEM(w)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+		errs <- err
+	}()
+
+	actual, err := ReadFragmentPEM(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, frags[0]) {
+		t.Fatalf("Expected %v but was %v", frags[0], actual)
+	}
+}
+
+func TestUnmarshalFragmentPEMQuoted(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := frags[0].MarshalPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// as it might look in a reply to an email, with Windows line endings
+	lines := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	quoted := "On Monday, Alice wrote:\r\n"
+	for _, l := range lines {
+		quoted += ">   " + l + "\r\n"
+	}
+
+	actual, err := UnmarshalFragmentPEM([]byte(quoted))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, frags[0]) {
+		t.Fatalf("Expected %v but was %v", frags[0], actual)
+	}
+}
+
+func TestUnmarshalFragmentPEMWrongType(t *testing.T) {
+	data := "-----BEGIN CERTIFICATE-----\nAA==\n-----END CERTIFICATE-----\n"
+
+	f, err := UnmarshalFragmentPEM([]byte(data))
+	if err == nil {
+		t.Fatalf("Expected error but got %v", f)
+	}
+
+	expected := `horcrux: unexpected PEM block type "CERTIFICATE"`
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
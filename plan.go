@@ -0,0 +1,192 @@
+package horcrux
+
+import (
+	"strings"
+	"time"
+
+	"github.com/codahale/chacha20"
+	"github.com/codahale/sss"
+	"golang.org/x/crypto/scrypt"
+)
+
+// CipherChaCha20Poly1305 is the name of the default cipher used to encrypt
+// shares.
+// Fragments with an empty Cipher field use ChaCha20Poly1305.
+const CipherChaCha20Poly1305 = "ChaCha20Poly1305"
+
+const calibrationScryptN = 16
+
+// SplitPlan is a prediction of the results of splitting a secret.
+type SplitPlan struct {
+	N                            int           // N is the number of fragments.
+	K                            int           // K is the number required to recover.
+	EstimatedFragmentSize        int           // EstimatedFragmentSize is the largest binary encoding, in bytes.
+	EstimatedDurationPerFragment time.Duration // EstimatedDurationPerFragment is the predicted key derivation time.
+	KDF                          string        // KDF is the key derivation algorithm.
+	Cipher                       string        // Cipher is the share encryption algorithm.
+}
+
+// PlanSplit checks the given arguments and options and predicts what splitting
+// the secret with them would produce, without deriving any keys at full
+// strength. The duration estimate is extrapolated from a key derivation at the
+// lowest possible cost.
+func PlanSplit(secret []byte, questions map[string]string, k int, opts ...SplitOption) (*SplitPlan, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	questions, err := normalizeQuestions(questions)
+	if err != nil {
+		return nil, err
+	}
+
+	// check k and n the same way Split does, without splitting the secret
+	if _, err := sss.Split(byte(len(questions)), byte(k), []byte{0}); err != nil {
+		return nil, err
+	}
+
+	size, err := estimateFragmentSize(secret, questions, k, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := calibrate(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SplitPlan{
+		N:                            len(questions),
+		K:                            k,
+		EstimatedFragmentSize:        size,
+		EstimatedDurationPerFragment: d,
+		KDF:                          cfg.kdf,
+		Cipher:                       cipherName(&cfg),
+	}, nil
+}
+
+// cipherName returns the name of the cipher the given split would use.
+func cipherName(cfg *splitConfig) string {
+	if cfg.aead != nil {
+		return cfg.aeadName
+	}
+	return CipherChaCha20Poly1305
+}
+
+// estimateFragmentSize returns the size of the binary encoding of the largest
+// fragment the given split would produce.
+func estimateFragmentSize(secret []byte, questions map[string]string, k int, cfg *splitConfig) (int, error) {
+	var params []byte
+	if cfg.customKDF != nil {
+		var err error
+		params, err = cfg.customKDF.MarshalParams()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	aead, name, err := cfg.newAEAD(make([]byte, chacha20.KeySize))
+	if err != nil {
+		return 0, err
+	}
+
+	max := 0
+	for q, a := range questions {
+		frag := Fragment{
+			Version:         FragmentVersion2,
+			ID:              byte(len(questions)),
+			SetID:           make([]byte, setIDSize),
+			K:               k,
+			N:               cfg.n,
+			R:               cfg.r,
+			P:               cfg.p,
+			KDF:             cfg.kdf,
+			BcryptCost:      cfg.bcryptCost,
+			Argon2Time:      cfg.argon2Time,
+			Argon2Memory:    cfg.argon2Memory,
+			Argon2Threads:   cfg.argon2Threads,
+			KDFParams:       params,
+			Question:        q,
+			AnswerHint:      cfg.hints[q],
+			NormalizerID:    cfg.normalizerID,
+			Locale:          cfg.locale,
+			AnswerSeparator: cfg.answerSeparator,
+			Cipher:          name,
+			Nonce:           make([]byte, aead.NonceSize()),
+			Salt:            make([]byte, saltLen),
+			Value:           make([]byte, len(secret)*cfg.weight(q)+aead.Overhead()),
+			Weight:          cfg.weights[q],
+			BoundParams:     true,
+		}
+
+		frag.Checksum = frag.ComputeChecksum()
+		frag.StructureChecksum = frag.ComputeStructureChecksum()
+
+		if cfg.privateQuestions {
+			frag.Question = strings.Repeat("0", 2*len(hashQuestion(q)))
+			frag.QuestionIsHashed = true
+		}
+
+		for range cfg.alternates[q] {
+			frag.Alternates = append(frag.Alternates, Alternate{
+				Salt:  frag.Salt,
+				Nonce: frag.Nonce,
+				Value: frag.Value,
+			})
+		}
+
+		if cfg.escrowKey != nil {
+			frag.EscrowedAnswer = make([]byte, GCMNonceSize+len(a)+GCMOverhead)
+		}
+
+		b, err := frag.MarshalBinary()
+		if err != nil {
+			return 0, err
+		}
+
+		if len(b) > max {
+			max = len(b)
+		}
+	}
+	return max, nil
+}
+
+// calibrate derives a key at the lowest cost for the configured KDF and scales
+// the time taken up to the configured cost. Custom KDFs are timed as-is.
+func calibrate(cfg *splitConfig) (time.Duration, error) {
+	salt := make([]byte, saltLen)
+
+	start := time.Now()
+	if cfg.kdf == KDFBcrypt {
+		if _, err := bcryptKey(nil, salt, MinBcryptCost); err != nil {
+			return 0, err
+		}
+		return time.Since(start) << uint(cfg.bcryptCost-MinBcryptCost), nil
+	}
+
+	if cfg.customKDF != nil {
+		if _, err := cfg.customKDF.Derive(nil, salt); err != nil {
+			return 0, err
+		}
+		return time.Since(start), nil
+	}
+
+	if cfg.kdf == KDFArgon2id {
+		_, err := argon2Key(nil, salt, 1, cfg.argon2Memory, cfg.argon2Threads)
+		if err != nil {
+			return 0, err
+		}
+		return time.Since(start) * time.Duration(cfg.argon2Time), nil
+	}
+
+	_, err := scrypt.Key(nil, salt, calibrationScryptN, cfg.r, cfg.p, 32)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(start) * time.Duration(cfg.n/calibrationScryptN), nil
+}
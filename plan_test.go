@@ -0,0 +1,124 @@
+package horcrux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlanSplit(t *testing.T) {
+	plan, err := PlanSplit(secret, questions, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.K != 2 {
+		t.Errorf("Expected %v but was %v", 2, plan.K)
+	}
+
+	if plan.N != len(questions) {
+		t.Errorf("Expected %v but was %v", len(questions), plan.N)
+	}
+
+	if plan.KDF != KDFScrypt {
+		t.Errorf("Expected %v but was %v", KDFScrypt, plan.KDF)
+	}
+
+	if plan.Cipher != CipherChaCha20Poly1305 {
+		t.Errorf("Expected %v but was %v", CipherChaCha20Poly1305, plan.Cipher)
+	}
+
+	if plan.EstimatedDurationPerFragment <= 0 {
+		t.Errorf("Expected a positive duration but was %v",
+			plan.EstimatedDurationPerFragment)
+	}
+}
+
+func TestPlanSplitFragmentSize(t *testing.T) {
+	plan, err := PlanSplit(secret, questions, 2, WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	max := 0
+	for _, f := range frags {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(b) > max {
+			max = len(b)
+		}
+	}
+
+	if plan.EstimatedFragmentSize != max {
+		t.Fatalf("Expected %v but was %v", max, plan.EstimatedFragmentSize)
+	}
+}
+
+func TestPlanSplitBadThreshold(t *testing.T) {
+	if _, err := PlanSplit(secret, questions, 1); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestPlanSplitFasterThanSplit(t *testing.T) {
+	start := time.Now()
+	if _, err := PlanSplit(secret, questions, 2); err != nil {
+		t.Fatal(err)
+	}
+	planned := time.Since(start)
+
+	start = time.Now()
+	if _, err := Split(secret, questions, 2, 0, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	split := time.Since(start)
+
+	if planned*100 > split {
+		t.Fatalf("Expected PlanSplit (%v) to be 100x faster than Split (%v)",
+			planned, split)
+	}
+}
+
+func TestPlanSplitCustomAEAD(t *testing.T) {
+	opts := []SplitOption{
+		WithScryptParams(2<<10, 8, 1),
+		WithSplitAEAD("AES-256-GCM", newGCM),
+	}
+
+	plan, err := PlanSplit(secret, questions, 2, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.Cipher != "AES-256-GCM" {
+		t.Fatalf("Expected %v but was %v", "AES-256-GCM", plan.Cipher)
+	}
+
+	frags, err := Split(secret, questions, 2, 0, 0, 0, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	max := 0
+	for _, f := range frags {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(b) > max {
+			max = len(b)
+		}
+	}
+
+	if plan.EstimatedFragmentSize != max {
+		t.Fatalf("Expected %v but was %v", max, plan.EstimatedFragmentSize)
+	}
+}
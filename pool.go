@@ -0,0 +1,62 @@
+package horcrux
+
+import (
+	"errors"
+	"sync"
+)
+
+// FragmentPool is a pool of fragments, used by SplitPooled to reduce
+// allocations when many secrets are split.
+var FragmentPool = sync.Pool{New: func() interface{} { return &Fragment{} }}
+
+// GetFragment returns a fragment from FragmentPool.
+func GetFragment() *Fragment {
+	return FragmentPool.Get().(*Fragment)
+}
+
+// PutFragment zeroes the fragment's byte slices and returns it to
+// FragmentPool. The fragment must not be used afterwards.
+func PutFragment(f *Fragment) {
+	f.Zeroize()
+	FragmentPool.Put(f)
+}
+
+// SplitPooled splits the given secret like SplitWithResult, but the returned
+// fragments are taken from FragmentPool, reusing the storage of fragments
+// previously returned with PutFragment. Callers should return the fragments
+// with PutFragment once they are done with them. Since only fragments are
+// returned, WithExtraShares and WithRequiredQuestion aren't supported.
+func SplitPooled(secret []byte, questions map[string]string, k int, opts ...SplitOption) ([]*Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.extraShares > 0 {
+		return nil, errors.New("horcrux: extra shares can't be used with SplitPooled")
+	}
+
+	if cfg.requiredQuestion != "" {
+		return nil, errRequiredQuestion
+	}
+
+	questions, err := normalizeQuestions(questions)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, _, err := shareSecret(secret, questions, k, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroShares(shares)
+
+	frags, _, err := encryptSharesTo(shares, questions, k, &cfg, GetFragment)
+	if err != nil {
+		for _, f := range frags {
+			PutFragment(f)
+		}
+		return nil, err
+	}
+	return frags, nil
+}
@@ -0,0 +1,141 @@
+package horcrux
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestSplitPooled(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 2; j++ {
+				frags, err := SplitPooled(secret, questions, 2,
+					WithScryptParams(2<<3, 1, 1))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				answers := make([]Answer, len(frags))
+				for i, f := range frags {
+					answers[i] = f.Clone().WithAnswer(questions[f.Question])
+				}
+
+				actual, err := Recover(answers)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				if !bytes.Equal(actual, secret) {
+					t.Errorf("Expected %v but was %v", secret, actual)
+				}
+
+				for _, f := range frags {
+					PutFragment(f)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSplitPooledWeighted(t *testing.T) {
+	frags, err := SplitPooled(secret, questions, 3,
+		WithScryptParams(2<<3, 1, 1),
+		WithWeights(map[string]int{weightedQuestion: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var answers []Answer
+	for _, f := range frags {
+		if f.Question == weightedQuestion {
+			if f.Weight != 2 {
+				t.Fatalf("Expected %v but was %v", 2, f.Weight)
+			}
+			answers = append(answers, f.Clone().WithAnswer(questions[f.Question]))
+		}
+	}
+	for _, f := range frags {
+		if f.Question != weightedQuestion {
+			answers = append(answers, f.Clone().WithAnswer(questions[f.Question]))
+			break
+		}
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestSplitPooledExtraShares(t *testing.T) {
+	_, err := SplitPooled(secret, questions, 2,
+		WithScryptParams(2<<3, 1, 1),
+		WithExtraShares(1),
+		WithExtraShareKey(make([]byte, 32)))
+
+	expected := "horcrux: extra shares can't be used with SplitPooled"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestSplitPooledTooManyShares(t *testing.T) {
+	_, err := SplitPooled(secret, questions, 2,
+		WithScryptParams(2<<3, 1, 1),
+		WithWeights(map[string]int{weightedQuestion: 253}))
+
+	expected := "horcrux: too many shares (256)"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestPutFragment(t *testing.T) {
+	f := GetFragment()
+	f.Value = []byte("share")
+
+	v := f.Value
+	PutFragment(f)
+
+	if !bytes.Equal(v, make([]byte, len(v))) {
+		t.Fatalf("Expected %x to be zeroed", v)
+	}
+}
+
+func BenchmarkSplit(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, err := SplitWithResult(secret, questions, 2,
+			WithScryptParams(2<<3, 1, 1))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSplitPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		frags, err := SplitPooled(secret, questions, 2,
+			WithScryptParams(2<<3, 1, 1))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, f := range frags {
+			PutFragment(f)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package horcrux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ReadAnswer prints the fragment's question to stderr and reads the answer from
+// in, without echoing it if in is a terminal. Leading and trailing whitespace
+// is trimmed from the answer.
+func ReadAnswer(f Fragment, in *os.File) (Answer, error) {
+	answers, err := ReadAnswers([]Fragment{f}, in)
+	if err != nil {
+		return Answer{}, err
+	}
+	return answers[0], nil
+}
+
+// ReadAnswers reads an answer for each of the given fragments in turn, like
+// ReadAnswer.
+func ReadAnswers(frags []Fragment, in *os.File) ([]Answer, error) {
+	return readAnswers(frags, os.Stderr, answerReader(in))
+}
+
+// answerReader returns a function which reads successive answers from in,
+// without echoing them if in is a terminal.
+func answerReader(in *os.File) func() (string, error) {
+	if fd := int(in.Fd()); term.IsTerminal(fd) {
+		return func() (string, error) {
+			b, err := term.ReadPassword(fd)
+			fmt.Fprintln(os.Stderr)
+			return string(b), err
+		}
+	}
+	return lineReader(in)
+}
+
+// readAnswers prompts for the answer to each fragment's question on w and
+// reads it with read.
+func readAnswers(frags []Fragment, w io.Writer, read func() (string, error)) ([]Answer, error) {
+	answers := make([]Answer, 0, len(frags))
+	for _, f := range frags {
+		prompt(w, f)
+
+		a, err := read()
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, f.WithAnswer(strings.TrimSpace(a)))
+	}
+	return answers, nil
+}
+
+// prompt prints the fragment's question and hint, if any, to w.
+func prompt(w io.Writer, f Fragment) {
+	if f.AnswerHint != "" {
+		fmt.Fprintf(w, "%s (hint: %s) ", f.Question, f.AnswerHint)
+	} else {
+		fmt.Fprintf(w, "%s ", f.Question)
+	}
+}
+
+// lineReader returns a function which reads successive lines from r.
+func lineReader(r io.Reader) func() (string, error) {
+	br := bufio.NewReader(r)
+	return func() (string, error) {
+		s, err := br.ReadString('\n')
+		if err == io.EOF && s != "" {
+			err = nil
+		}
+		return s, err
+	}
+}
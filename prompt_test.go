@@ -0,0 +1,70 @@
+package horcrux
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestReadAnswers(t *testing.T) {
+	frags := []Fragment{
+		{ID: 1, Question: "What's your first pet's name?"},
+		{ID: 2, Question: "What's your real name?", AnswerHint: "not Bob"},
+	}
+
+	in := bytes.NewReader([]byte("  Spot \n\tRumplestiltskin"))
+	out := new(bytes.Buffer)
+
+	answers, err := readAnswers(frags, out, lineReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range []string{"Spot", "Rumplestiltskin"} {
+		if answers[i].Answer != expected {
+			t.Errorf("Expected %q but was %q", expected, answers[i].Answer)
+		}
+
+		if answers[i].ID != frags[i].ID {
+			t.Errorf("Expected %v but was %v", frags[i].ID, answers[i].ID)
+		}
+	}
+
+	expected := "What's your first pet's name? " +
+		"What's your real name? (hint: not Bob) "
+	actual := out.String()
+	if actual != expected {
+		t.Fatalf("Expected %q but was %q", expected, actual)
+	}
+}
+
+func TestReadAnswersTooFew(t *testing.T) {
+	frags := []Fragment{{ID: 1}, {ID: 2}}
+
+	in := bytes.NewReader([]byte("Spot\n"))
+	if _, err := readAnswers(frags, new(bytes.Buffer), lineReader(in)); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestReadAnswer(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if _, err := w.WriteString(" Spot\n"); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	a, err := ReadAnswer(Fragment{ID: 1, Question: "Pet?"}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a.Answer != "Spot" {
+		t.Fatalf("Expected %q but was %q", "Spot", a.Answer)
+	}
+}
@@ -0,0 +1,90 @@
+package horcrux
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sort"
+)
+
+// A zero-knowledge proof that encrypted shares lie on a single polynomial of
+// degree k-1 would require a share encoding with homomorphic commitments, which
+// the bytewise GF(2^8) shares from the sss package don't have. Instead, a split
+// proof is an Ed25519 signature of the threshold, the number of fragments, and
+// the exact contents of every fragment, so that anyone holding the public key
+// can check a fragment set for tampering or substitution without the secret or
+// any answers. A proof is only as trustworthy as the public key it's checked
+// with, and it does not prove that the original split was performed honestly.
+
+const proofVersion = 1
+
+// SplitAndProve splits the given secret like SplitWithResult and also returns a
+// proof, signed with the given key, which VerifySplitProof can use to check the
+// fragments.
+func SplitAndProve(secret []byte, questions map[string]string, k int, key ed25519.PrivateKey, opts ...SplitOption) (frags []Fragment, proof []byte, err error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, nil, errors.New("horcrux: invalid proof signing key")
+	}
+
+	res, err := SplitWithResult(secret, questions, k, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msg, err := splitProofMessage(res.Fragments, k)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Fragments, append(msg[:3:3], ed25519.Sign(key, msg)...), nil
+}
+
+// VerifySplitProof returns true if the given fragments are exactly those which
+// the proof was created for, and the proof was signed by the private half of
+// the given public key.
+func VerifySplitProof(frags []Fragment, proof []byte, pub ed25519.PublicKey) bool {
+	if len(pub) != ed25519.PublicKeySize ||
+		len(proof) != 3+ed25519.SignatureSize || proof[0] != proofVersion ||
+		int(proof[2]) != len(frags) {
+		return false
+	}
+
+	k := int(proof[1])
+	ids := make(map[byte]bool, len(frags))
+	for _, f := range frags {
+		// weighted fragments hold several consecutive IDs, so IDs can skip
+		if f.K != k || f.ID < 1 || ids[f.ID] {
+			return false
+		}
+		ids[f.ID] = true
+	}
+
+	msg, err := splitProofMessage(frags, k)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, msg, proof[3:])
+}
+
+// splitProofMessage returns the signed message of a split proof: the version,
+// threshold, and number of fragments, followed by the SHA-256 hash of each
+// fragment's ID, weight, and binary encoding, in ID order.
+func splitProofMessage(frags []Fragment, k int) ([]byte, error) {
+	sorted := append([]Fragment(nil), frags...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	h := sha256.New()
+	for _, f := range sorted {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		h.Write([]byte{f.ID, byte(f.weight())})
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(len(b))))
+		h.Write(b)
+	}
+
+	return h.Sum([]byte{proofVersion, byte(k), byte(len(frags))}), nil
+}
@@ -0,0 +1,107 @@
+package horcrux
+
+import (
+	"crypto/ed25519"
+	"testing"
+)
+
+func newProofKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, priv
+}
+
+func TestSplitAndProve(t *testing.T) {
+	pub, priv := newProofKey(t)
+	frags, proof, err := SplitAndProve(secret, questions, 2, priv,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !VerifySplitProof(frags, proof, pub) {
+		t.Fatal("Proof failed verification")
+	}
+
+	reversed := make([]Fragment, len(frags))
+	for i, f := range frags {
+		reversed[len(frags)-1-i] = f
+	}
+
+	if !VerifySplitProof(reversed, proof, pub) {
+		t.Fatal("Proof failed verification for reordered fragments")
+	}
+}
+
+func TestVerifySplitProofTamperedShare(t *testing.T) {
+	pub, priv := newProofKey(t)
+	frags, proof, err := SplitAndProve(secret, questions, 2, priv,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags[1] = frags[1].WithQuestion(frags[1].Question)
+	frags[1].Value[0] ^= 1
+
+	if VerifySplitProof(frags, proof, pub) {
+		t.Fatal("Proof passed verification for a tampered share")
+	}
+}
+
+func TestVerifySplitProofMissingFragment(t *testing.T) {
+	pub, priv := newProofKey(t)
+	frags, proof, err := SplitAndProve(secret, questions, 2, priv,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if VerifySplitProof(frags[1:], proof, pub) {
+		t.Fatal("Proof passed verification with a missing fragment")
+	}
+}
+
+func TestVerifySplitProofWrongKey(t *testing.T) {
+	pub, _ := newProofKey(t)
+	otherPub, otherPriv := newProofKey(t)
+	frags, proof, err := SplitAndProve(secret, questions, 2, otherPriv,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !VerifySplitProof(frags, proof, otherPub) {
+		t.Fatal("Proof failed verification")
+	}
+
+	if VerifySplitProof(frags, proof, pub) {
+		t.Fatal("Proof passed verification with the wrong key")
+	}
+}
+
+func TestSplitAndProveWeighted(t *testing.T) {
+	pub, priv := newProofKey(t)
+	frags, proof, err := SplitAndProve(secret, questions, 3, priv,
+		WithScryptParams(2<<10, 8, 1),
+		WithWeights(map[string]int{"What's your first pet's name?": 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !VerifySplitProof(frags, proof, pub) {
+		t.Fatal("Proof failed verification for weighted fragments")
+	}
+
+	for i := range frags {
+		if frags[i].Weight == 2 {
+			frags[i].Weight = 1
+		}
+	}
+
+	if VerifySplitProof(frags, proof, pub) {
+		t.Fatal("Proof passed verification with a changed weight")
+	}
+}
@@ -0,0 +1,78 @@
+package horcrux
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// splitCase is a randomly generated secret, set of questions, and threshold.
+type splitCase struct {
+	Secret    []byte
+	Questions map[string]string
+	K         int
+}
+
+func (splitCase) Generate(r *rand.Rand, size int) reflect.Value {
+	c := splitCase{
+		Secret:    make([]byte, 1+r.Intn(255)),
+		Questions: make(map[string]string),
+	}
+	r.Read(c.Secret)
+
+	n := 2 + r.Intn(7)
+	for i := 0; i < n; i++ {
+		c.Questions[fmt.Sprintf("question %d?", i)] = fmt.Sprintf("%x", r.Int63())
+	}
+	c.K = 2 + r.Intn(n-1)
+
+	return reflect.ValueOf(c)
+}
+
+func (c splitCase) answers(t *testing.T) []Answer {
+	frags, err := Split(c.Secret, c.Questions, c.K, 2<<3, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = f.WithAnswer(c.Questions[f.Question])
+	}
+	return answers
+}
+
+var propertyConfig = &quick.Config{MaxCount: 50}
+
+func TestSplitRecoverProperty(t *testing.T) {
+	f := func(c splitCase) bool {
+		actual, err := Recover(c.answers(t))
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		return bytes.Equal(actual, c.Secret)
+	}
+
+	if err := quick.Check(f, propertyConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWrongAnswerProperty(t *testing.T) {
+	f := func(c splitCase, wrong uint8) bool {
+		answers := c.answers(t)[:c.K]
+		i := int(wrong) % len(answers)
+		answers[i].Answer += "wrong"
+
+		_, err := Recover(answers)
+		return err != nil
+	}
+
+	if err := quick.Check(f, propertyConfig); err != nil {
+		t.Fatal(err)
+	}
+}
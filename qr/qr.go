@@ -0,0 +1,78 @@
+// Package qr renders horcrux fragments as QR codes and decodes the payloads of
+// scanned QR codes back into fragments, for distributing fragments on paper.
+package qr
+
+import (
+	"strings"
+
+	"github.com/codahale/horcrux"
+	"github.com/skip2/go-qrcode"
+)
+
+// DefaultSize is the width, in pixels, of images rendered with a size of zero.
+const DefaultSize = 256
+
+// Payload returns the text encoded in the fragment's QR code: the fragment's
+// URL form, which phone cameras recognize and which, unlike binary data,
+// survives scanners which only handle text.
+func Payload(f horcrux.Fragment) (string, error) {
+	// URLString returns an empty string if the fragment can't be encoded
+	if _, err := f.MarshalBinary(); err != nil {
+		return "", err
+	}
+	return f.URLString(), nil
+}
+
+// Encode renders the fragment's payload as a PNG image of a QR code with the
+// given error correction level, size pixels wide.
+func Encode(f horcrux.Fragment, level horcrux.QRCorrectionLevel, size int) ([]byte, error) {
+	p, err := Payload(f)
+	if err != nil {
+		return nil, err
+	}
+	return Encoder{Size: size}.Encode([]byte(p), int(level))
+}
+
+// Decode decodes a fragment from the payload of a scanned QR code. It accepts
+// both the URL payloads rendered by Encode and the binary payloads rendered by
+// horcrux.FragmentToQRCode.
+func Decode(payload []byte) (horcrux.Fragment, error) {
+	s := strings.TrimSpace(string(payload))
+	if strings.HasPrefix(s, horcrux.URLScheme+"://") {
+		return horcrux.ParseURLFragment(s)
+	}
+
+	var f horcrux.Fragment
+	err := f.UnmarshalBinary(payload)
+	return f, err
+}
+
+// Encoder renders QR codes as PNG images. It implements horcrux.QRCodeEncoder,
+// for use with horcrux.WithQRCodeEncoder.
+type Encoder struct {
+	Size int // Size is the width of the images, in pixels.
+}
+
+// Encode renders the data as a PNG image of a QR code with the given error
+// correction level, one of the horcrux.QRCorrectionLevel values.
+func (e Encoder) Encode(data []byte, level int) ([]byte, error) {
+	size := e.Size
+	if size == 0 {
+		size = DefaultSize
+	}
+	return qrcode.Encode(string(data), recoveryLevel(level), size)
+}
+
+// recoveryLevel returns the go-qrcode recovery level for the given correction
+// level.
+func recoveryLevel(level int) qrcode.RecoveryLevel {
+	switch horcrux.QRCorrectionLevel(level) {
+	case horcrux.QRCorrectionLow:
+		return qrcode.Low
+	case horcrux.QRCorrectionQuartile:
+		return qrcode.High
+	case horcrux.QRCorrectionHigh:
+		return qrcode.Highest
+	}
+	return qrcode.Medium
+}
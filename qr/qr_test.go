@@ -0,0 +1,93 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"github.com/codahale/horcrux"
+	"github.com/codahale/horcrux/testhelpers"
+)
+
+var questions = map[string]string{
+	"What's your first pet's name?":     "Spot",
+	"What's your least favorite food?":  "broccoli",
+	"What's your mother's maiden name?": "Hernandez",
+}
+
+func TestEncode(t *testing.T) {
+	frags := testhelpers.MustSplitFast(t, []byte("secret"), questions, 2)
+
+	img, err := Encode(frags[0], horcrux.QRCorrectionMedium, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Width != 300 || cfg.Height != 300 {
+		t.Fatalf("Expected a 300x300 image but was %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+func TestDecodeURLPayload(t *testing.T) {
+	frags := testhelpers.MustSplitFast(t, []byte("secret"), questions, 2)
+
+	p, err := Payload(frags[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// scanners often add a trailing newline
+	actual, err := Decode([]byte(p + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, frags[0]) {
+		t.Fatalf("Expected %v but was %v", frags[0], actual)
+	}
+}
+
+func TestDecodeBinaryPayload(t *testing.T) {
+	frags := testhelpers.MustSplitFast(t, []byte("secret"), questions, 2)
+
+	b, err := frags[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, frags[0]) {
+		t.Fatalf("Expected %v but was %v", frags[0], actual)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	horcrux.WithQRCodeEncoder(Encoder{})
+	defer horcrux.WithQRCodeEncoder(nil)
+
+	frags := testhelpers.MustSplitFast(t, []byte("secret"), questions, 2)
+
+	img, err := horcrux.FragmentToQRCode(frags[0], horcrux.QRCorrectionHigh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Width != DefaultSize {
+		t.Fatalf("Expected %v but was %v", DefaultSize, cfg.Width)
+	}
+}
@@ -0,0 +1,60 @@
+package horcrux
+
+import (
+	"errors"
+	"sync"
+)
+
+// QRCorrectionLevel is the error correction level of a QR code.
+type QRCorrectionLevel int
+
+// QR code error correction levels, from least to most redundant.
+const (
+	QRCorrectionLow      QRCorrectionLevel = iota // Recovers 7% of data.
+	QRCorrectionMedium                            // Recovers 15% of data.
+	QRCorrectionQuartile                          // Recovers 25% of data.
+	QRCorrectionHigh                              // Recovers 30% of data.
+)
+
+// QRCodeEncoder renders data as a QR code image with the given error
+// correction level, one of the QRCorrectionLevel values.
+type QRCodeEncoder interface {
+	Encode(data []byte, level int) ([]byte, error)
+}
+
+// ErrNoQRCodeEncoder is returned by FragmentToQRCode if no QR code encoder has
+// been registered with WithQRCodeEncoder.
+var ErrNoQRCodeEncoder = errors.New("horcrux: no QR code encoder registered")
+
+var (
+	qrMu      sync.RWMutex
+	qrEncoder QRCodeEncoder
+)
+
+// WithQRCodeEncoder registers the encoder used by FragmentToQRCode. The qr
+// subpackage provides one which renders PNG images.
+func WithQRCodeEncoder(enc QRCodeEncoder) {
+	qrMu.Lock()
+	defer qrMu.Unlock()
+
+	qrEncoder = enc
+}
+
+// FragmentToQRCode renders the fragment's binary encoding as a QR code image
+// using the registered QR code encoder.
+func FragmentToQRCode(f Fragment, level QRCorrectionLevel) ([]byte, error) {
+	qrMu.RLock()
+	enc := qrEncoder
+	qrMu.RUnlock()
+
+	if enc == nil {
+		return nil, ErrNoQRCodeEncoder
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return enc.Encode(b, int(level))
+}
@@ -0,0 +1,54 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+type fakeQRCodeEncoder struct {
+	data  []byte
+	level int
+}
+
+func (e *fakeQRCodeEncoder) Encode(data []byte, level int) ([]byte, error) {
+	e.data = data
+	e.level = level
+	return []byte("PNG"), nil
+}
+
+func TestFragmentToQRCode(t *testing.T) {
+	enc := &fakeQRCodeEncoder{}
+	WithQRCodeEncoder(enc)
+	defer WithQRCodeEncoder(nil)
+
+	f := Fragment{ID: 1, K: 2, Question: "Q", Value: []byte{1, 2, 3}}
+
+	img, err := FragmentToQRCode(f, QRCorrectionQuartile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(img) != "PNG" {
+		t.Fatalf("Expected %q but was %q", "PNG", img)
+	}
+
+	expected, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(enc.data, expected) {
+		t.Fatalf("Expected %x but was %x", expected, enc.data)
+	}
+
+	if enc.level != int(QRCorrectionQuartile) {
+		t.Fatalf("Expected level %d but was %d", QRCorrectionQuartile, enc.level)
+	}
+}
+
+func TestFragmentToQRCodeWithoutEncoder(t *testing.T) {
+	img, err := FragmentToQRCode(Fragment{}, QRCorrectionLow)
+	if err != ErrNoQRCodeEncoder {
+		t.Fatalf("Expected %v but was %v (%v)", ErrNoQRCodeEncoder, err, img)
+	}
+}
@@ -0,0 +1,27 @@
+package horcrux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MatchQuestion returns true if the fragment is for the given question. If the
+// fragment's question is hashed, the given question is hashed and compared.
+func MatchQuestion(f Fragment, question string) bool {
+	question = norm.NFC.String(question)
+	if f.QuestionIsHashed {
+		return f.Question == hex.EncodeToString(hashQuestion(question))
+	}
+	return f.Question == question
+}
+
+// hashQuestion returns the SHA-256 hash of the question in Unicode
+// Normalization Form C. The hash is used as the associated data when
+// encrypting the share, so the share can only be decrypted by someone who knows
+// the question.
+func hashQuestion(q string) []byte {
+	h := sha256.Sum256([]byte(norm.NFC.String(q)))
+	return h[:]
+}
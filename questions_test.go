@@ -0,0 +1,81 @@
+package horcrux
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitPrivateQuestions(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithPrivateQuestions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if !f.QuestionIsHashed {
+			t.Fatalf("Expected a hashed question but was %v", f)
+		}
+
+		for q := range questions {
+			if strings.Contains(f.String(), q) {
+				t.Fatalf("Fragment contains question %q: %v", q, f)
+			}
+		}
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		for q, a := range questions {
+			if MatchQuestion(frags[i], q) {
+				answers[i] = frags[i].WithQuestion(q).WithAnswer(a)
+			}
+		}
+	}
+
+	s, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestRecoverPrivateQuestionsWrongQuestion(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithPrivateQuestions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		for q, a := range questions {
+			if MatchQuestion(frags[i], q) {
+				answers[i] = frags[i].WithQuestion(q + "?").WithAnswer(a)
+			}
+		}
+	}
+
+	s, err := Recover(answers)
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	expected := "horcrux: wrong answers for fragments [1 2] (decrypted [])"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestMatchQuestion(t *testing.T) {
+	f := Fragment{Question: "Café?"}
+	if !MatchQuestion(f, "Café?") {
+		t.Fatal("Expected question to match")
+	}
+
+	if MatchQuestion(f, "Tea?") {
+		t.Fatal("Expected question not to match")
+	}
+}
@@ -0,0 +1,151 @@
+package horcrux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RefreshFragments refreshes the shares of a fragment set without recovering
+// the secret, like ProactiveResplit with an unchanged threshold, so that any
+// old fragments which may have leaked can no longer be combined with the new
+// ones. Unlike ProactiveResplit, each fragment is re-encrypted with its own
+// KDF parameters, answer hint, normalizer, locale, answer separator, attempt
+// limit, and metadata, unless overridden with the given options. Alternate
+// answers are not known, so they are not kept. K answers are enough to recover
+// the secret, but every share must be re-encrypted, so only the fragments of
+// the given answers are refreshed; any others are left out of the new set.
+func RefreshFragments(answers []Answer, opts ...SplitOption) ([]Fragment, error) {
+	if len(answers) == 0 {
+		return nil, errors.New("horcrux: no fragments to refresh")
+	}
+
+	k := answers[0].K
+	if have := answersWeight(answers); have < k {
+		return nil, ErrTooFewAnswers{Need: k, Have: have}
+	}
+
+	if err := checkSets(answers); err != nil {
+		return nil, err
+	}
+
+	if err := checkUngrouped(answers); err != nil {
+		return nil, err
+	}
+
+	cfgs := make([]splitConfig, len(answers))
+	for i, a := range answers {
+		if a.K != k {
+			return nil, fmt.Errorf("horcrux: fragment %d needs %d answers but fragment %d needs %d",
+				a.ID, a.K, answers[0].ID, k)
+		}
+
+		if a.QuestionIsHashed {
+			return nil, errors.New("horcrux: cannot refresh a fragment with a private question")
+		}
+
+		cfg, err := fragmentSplitConfig(a.Fragment)
+		if err != nil {
+			return nil, err
+		}
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+
+		if err := cfg.validate(); err != nil {
+			return nil, err
+		}
+		cfgs[i] = cfg
+	}
+
+	ctx, cancel := withTimeout(cfgs[0].ctx, cfgs[0].timeout)
+	defer cancel()
+
+	shares, err := openShares(ctx, answers, &cfgs[0])
+	defer func() {
+		for _, s := range shares {
+			zero(s)
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	maxID := byte(0)
+	for id := range shares {
+		if id > maxID {
+			maxID = id
+		}
+	}
+
+	if err := addZeroShares(shares, maxID, k); err != nil {
+		return nil, err
+	}
+
+	setID, err := newSetID(cfgs[0].random())
+	if err != nil {
+		return nil, err
+	}
+
+	frags := make([]Fragment, len(answers))
+	for i, a := range answers {
+		cfg := &cfgs[i]
+		cfg.setID = setID
+
+		normalize, err := cfg.normalizer()
+		if err != nil {
+			return nil, err
+		}
+
+		normalized := a.Answer
+		if normalize != nil {
+			normalized = normalize(a.Answer)
+		}
+
+		v, err := bundleShares(shares, a.ID, a.weight())
+		if err != nil {
+			return nil, err
+		}
+
+		err = encryptShare(ctx, &frags[i], a.ID, k, a.Question, normalized,
+			a.Answer, v, cfg)
+		zero(v)
+		if err != nil {
+			return nil, err
+		}
+
+		frags[i].MaxAttempts = a.MaxAttempts
+		frags[i].Metadata = a.Clone().Metadata
+	}
+	return frags, nil
+}
+
+// openShares decrypts the shares of the given answers, keyed by ID, using the
+// custom AEAD of the given split configuration, if any. Each share of a
+// weighted fragment is keyed by its own ID. The shares decrypted before any
+// error are returned along with it, so they can be zeroed.
+func openShares(ctx context.Context, answers []Answer, cfg *splitConfig) (map[byte][]byte, error) {
+	var rcfg recoverConfig
+	if cfg.aead != nil {
+		rcfg.aeads = map[string]AEADFunc{cfg.aeadName: cfg.aead}
+	}
+
+	shares := make(map[byte][]byte, len(answers))
+	for _, a := range answers {
+		s, err := openShare(ctx, a, &rcfg)
+		if err != nil {
+			return shares, err
+		}
+
+		if _, ok := shares[s.id]; ok {
+			zero(s.value)
+			return shares, fmt.Errorf("horcrux: more than one answer for fragment %d",
+				s.id)
+		}
+
+		for _, p := range expandShares([]share{s}) {
+			shares[p.id] = p.value
+		}
+	}
+	return shares, nil
+}
@@ -0,0 +1,120 @@
+package horcrux
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestRefreshFragments(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithAnswerHint("What's your first pet's name?", "woof"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frags[0].Metadata = map[string]string{"owner": "alice"}
+
+	refreshed, err := RefreshFragments(allAnswers(frags))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, f := range refreshed {
+		old := frags[i]
+		if f.ID != old.ID || f.K != old.K || f.N != old.N ||
+			f.AnswerHint != old.AnswerHint {
+			t.Fatalf("Expected %v but was %v", old, f)
+		}
+
+		if bytes.Equal(f.SetID, old.SetID) {
+			t.Fatal("Expected a new set ID")
+		}
+	}
+
+	hinted := 0
+	for _, f := range refreshed {
+		if f.AnswerHint == "woof" {
+			hinted++
+		}
+	}
+	if hinted != 1 {
+		t.Fatalf("Expected %v but was %v", 1, hinted)
+	}
+
+	if refreshed[0].Metadata["owner"] != "alice" {
+		t.Fatalf("Expected %v but was %v", frags[0].Metadata, refreshed[0].Metadata)
+	}
+
+	answers := allAnswers(refreshed)
+	s, err := Recover(answers[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+
+	// old fragments can't be combined with refreshed ones
+	_, err = Recover([]Answer{answers[0], allAnswers(frags)[1]})
+	expected := ErrMixedSets{IDs: []int{int(frags[1].ID)}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRefreshFragmentsChangesShares(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := RefreshFragments(allAnswers(frags))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := DecryptShares(allAnswers(frags))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := DecryptShares(allAnswers(refreshed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, s := range after {
+		if bytes.Equal(s, before[id]) {
+			t.Fatalf("Expected share %d to change", id)
+		}
+	}
+}
+
+func TestRefreshFragmentsTooFewAnswers(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = RefreshFragments(allAnswers(frags)[:2])
+
+	expected := ErrTooFewAnswers{Need: 3, Have: 2}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRefreshFragmentsWrongAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := allAnswers(frags)
+	answers[1].Answer = "wrong"
+
+	if _, err := RefreshFragments(answers); !isAuthFailure(err) {
+		t.Fatalf("Expected authentication failure but was %v", err)
+	}
+}
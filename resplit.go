@@ -0,0 +1,133 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/codahale/sss"
+)
+
+// ProactiveResplit raises the threshold of a fragment set to newK without
+// recovering the secret. Zero is split into new shares with a threshold of
+// newK, and each zero share is added to the existing share with the same ID.
+// The sum of two polynomials with the same constant term and degrees K-1 and
+// newK-1 has that constant term and degree newK-1, so the summed shares
+// recover the original secret, but only with newK of them. Since every share
+// must be re-encrypted, an answer must be given for every fragment. If newK is
+// equal to K, the shares are refreshed instead, so that the old fragments can
+// no longer be combined with the new ones. The threshold cannot be lowered this
+// way. Unless overridden with WithScryptParams, the recommended scrypt
+// parameters are used.
+func ProactiveResplit(frags []Fragment, answers []Answer, newK int, opts ...SplitOption) ([]Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if len(frags) == 0 {
+		return nil, errors.New("horcrux: no fragments to re-split")
+	}
+
+	if newK < frags[0].K {
+		return nil, fmt.Errorf("horcrux: cannot lower threshold from %d to %d",
+			frags[0].K, newK)
+	}
+
+	n := 0
+	for _, f := range frags {
+		n += f.weight()
+	}
+
+	if newK > n {
+		return nil, fmt.Errorf("horcrux: need at least %d fragments but only have %d",
+			newK, n)
+	}
+
+	if err := checkUngrouped(answers); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[byte]Answer, len(answers))
+	for _, a := range answers {
+		byID[a.ID] = a
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	var rcfg recoverConfig
+	shares := make(map[byte][]byte, len(frags))
+	questions := make(map[string]string, len(frags))
+	weights := make(map[string]int, len(frags))
+	maxID := byte(0)
+	for _, f := range frags {
+		a, ok := byID[f.ID]
+		if !ok {
+			return nil, fmt.Errorf("horcrux: no answer for fragment %d", f.ID)
+		}
+
+		s, err := openShare(ctx, a, &rcfg)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range expandShares([]share{s}) {
+			shares[p.id] = p.value
+			if p.id > maxID {
+				maxID = p.id
+			}
+		}
+
+		questions[a.Question] = a.Answer
+		if f.Weight > 0 {
+			weights[a.Question] = f.Weight
+		}
+	}
+
+	if cfg.weights == nil {
+		cfg.weights = weights
+	}
+
+	if err := addZeroShares(shares, maxID, newK); err != nil {
+		return nil, err
+	}
+
+	questions, err := normalizeQuestions(questions)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := encryptShares(shares, questions, newK, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return res.Fragments, nil
+}
+
+// addZeroShares adds a share of zero with the given threshold to each of the
+// given shares. Addition in GF(2^8) is XOR. sss.Split evaluates its
+// polynomials at 1 through n, so every share ID up to maxID gets a zero share.
+func addZeroShares(shares map[byte][]byte, maxID byte, k int) error {
+	size := 0
+	for _, v := range shares {
+		size = len(v)
+		break
+	}
+
+	zeros, err := sss.Split(maxID, byte(k), make([]byte, size))
+	if err != nil {
+		return err
+	}
+
+	for id, v := range shares {
+		z := zeros[id]
+		if len(z) != len(v) {
+			return fmt.Errorf("horcrux: share %d has the wrong length", id)
+		}
+
+		for i := range v {
+			v[i] ^= z[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package horcrux
+
+import "testing"
+
+func allAnswers(frags []Fragment) []Answer {
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+	return answers
+}
+
+func TestProactiveResplit(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resplit, err := ProactiveResplit(frags, allAnswers(frags), 3,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := allAnswers(resplit)
+	for _, f := range resplit {
+		if f.K != 3 {
+			t.Fatalf("Expected K=3 but was %d", f.K)
+		}
+	}
+
+	s, err := Recover(answers[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+
+	// With K forced back to 2, two of the new shares no longer decrypt.
+	two := answers[:2]
+	for i := range two {
+		two[i].K = 2
+		two[i].StructureChecksum = two[i].ComputeStructureChecksum()
+	}
+
+	s, err = Recover(two)
+	if err == nil {
+		t.Fatalf("Expected error but got %v", s)
+	}
+}
+
+func TestProactiveResplitLowerThreshold(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resplit, err := ProactiveResplit(frags, allAnswers(frags), 2)
+	if err == nil {
+		t.Fatalf("Expected error but got %v", resplit)
+	}
+
+	expected := "horcrux: cannot lower threshold from 3 to 2"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestProactiveResplitMissingAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resplit, err := ProactiveResplit(frags, allAnswers(frags)[1:], 3)
+	if err == nil {
+		t.Fatalf("Expected error but got %v", resplit)
+	}
+}
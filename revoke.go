@@ -0,0 +1,166 @@
+package horcrux
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+)
+
+// ErrFragmentRevoked is returned when a revoked fragment is used for recovery.
+type ErrFragmentRevoked struct {
+	ID int // ID is the ID of the revoked fragment.
+}
+
+func (e ErrFragmentRevoked) Error() string {
+	return fmt.Sprintf("horcrux: fragment %d has been revoked", e.ID)
+}
+
+// RevokeFragment returns a copy of the given fragment which has been marked as
+// revoked and signed with the given administrator key. Recover will refuse to
+// use a revoked fragment.
+//
+// Marking a fragment is advisory: its holder still has the unrevoked original,
+// and can clear the Revoked field of the copy, since the signature only proves
+// that a fragment was revoked, not that one wasn't. To enforce revocation,
+// keep the revoked fragments and give them to Recover with WithRevocationList.
+func RevokeFragment(f Fragment, adminKey ed25519.PrivateKey) (Fragment, error) {
+	if len(adminKey) != ed25519.PrivateKeySize {
+		return f, fmt.Errorf("horcrux: bad revocation key length: %d",
+			len(adminKey))
+	}
+
+	f.Revoked = true
+	f.RevocationSignature = ed25519.Sign(adminKey, revocationMessage(f))
+	return f, nil
+}
+
+// checkRevocation returns ErrFragmentRevoked if the fragment has been revoked.
+// If a public key is given, the revocation's signature must be valid.
+func checkRevocation(f Fragment, pub ed25519.PublicKey) error {
+	if !f.Revoked {
+		return nil
+	}
+
+	if pub != nil && (len(pub) != ed25519.PublicKeySize ||
+		!ed25519.Verify(pub, revocationMessage(f), f.RevocationSignature)) {
+		return fmt.Errorf("horcrux: bad revocation signature for fragment %d",
+			f.ID)
+	}
+
+	return ErrFragmentRevoked{ID: int(f.ID)}
+}
+
+// checkRevocationList returns ErrFragmentRevoked if the fragment is one of the
+// given revoked fragments. If a public key is given, the matching revoked
+// fragment's signature must be valid.
+func checkRevocationList(f Fragment, revoked []Fragment, pub ed25519.PublicKey) error {
+	msg := revocationMessage(f)
+	for _, r := range revoked {
+		if r.Revoked && bytes.Equal(msg, revocationMessage(r)) {
+			return checkRevocation(r, pub)
+		}
+	}
+	return nil
+}
+
+// revocationMessage returns the message signed to revoke the fragment, which
+// identifies the fragment by its ID, question, salt, nonce, and value.
+func revocationMessage(f Fragment) []byte {
+	msg := []byte("horcrux revocation")
+	msg = append(msg, f.ID)
+	for _, b := range [][]byte{[]byte(f.Question), f.Salt, f.Nonce, f.Value} {
+		msg = binary.BigEndian.AppendUint32(msg, uint32(len(b)))
+		msg = append(msg, b...)
+	}
+	return msg
+}
+
+// Revoke removes a compromised question from a fragment set. It recovers the
+// secret, re-splits it with the same threshold for the remaining questions, and
+// returns the new fragments along with a tombstone: a copy of the revoked
+// fragment, marked as revoked, with a signature by the given administrator key
+// over its ID, question, salt, nonce, and encrypted share, as with
+// RevokeFragment. The new fragments have a new set ID, so none of the old
+// fragments, including the revoked one, can be combined with them. Since every
+// remaining share must be re-encrypted, an answer must be given for every
+// fragment but the revoked one; each is checked before re-splitting. Answer
+// hints and weights are kept, and the given options apply to the new fragments.
+func Revoke(frags []Fragment, revokeID int, answers []Answer, adminKey ed25519.PrivateKey, opts ...SplitOption) ([]Fragment, Fragment, error) {
+	if len(adminKey) != ed25519.PrivateKeySize {
+		return nil, Fragment{}, fmt.Errorf("horcrux: bad revocation key length: %d",
+			len(adminKey))
+	}
+
+	if err := checkUngrouped(answers); err != nil {
+		return nil, Fragment{}, err
+	}
+
+	byID := make(map[byte]Answer, len(answers))
+	for _, a := range answers {
+		byID[a.ID] = a
+	}
+
+	var revoked *Fragment
+	var remaining []Answer
+	questions := make(map[string]string, len(frags))
+	hints := make(map[string]string, len(frags))
+	weights := make(map[string]int, len(frags))
+	for i, f := range frags {
+		if int(f.ID) == revokeID {
+			revoked = &frags[i]
+			continue
+		}
+
+		a, ok := byID[f.ID]
+		if !ok {
+			return nil, Fragment{}, fmt.Errorf("horcrux: no answer for fragment %d",
+				f.ID)
+		}
+
+		remaining = append(remaining, a)
+		questions[a.Question] = a.Answer
+		if f.AnswerHint != "" {
+			hints[a.Question] = f.AnswerHint
+		}
+		if f.Weight > 0 {
+			weights[a.Question] = f.Weight
+		}
+	}
+
+	if revoked == nil {
+		return nil, Fragment{}, fmt.Errorf("horcrux: no fragment %d to revoke",
+			revokeID)
+	}
+
+	k := revoked.K
+	if have := answersWeight(remaining); have < k {
+		return nil, Fragment{}, ErrTooFewAnswers{Need: k, Have: have}
+	}
+
+	cfg := defaultSplitConfig()
+	cfg.hints = hints
+	cfg.weights = weights
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// every remaining answer is decrypted, so that none is re-encrypted wrong
+	secret, err := recoverSecret(remaining, &recoverConfig{ctx: cfg.ctx,
+		timeout: cfg.timeout})
+	if err != nil {
+		return nil, Fragment{}, err
+	}
+	defer zero(secret)
+
+	res, err := split(secret, questions, k, &cfg)
+	if err != nil {
+		return nil, Fragment{}, err
+	}
+
+	tombstone, err := RevokeFragment(revoked.Clone(), adminKey)
+	if err != nil {
+		return nil, Fragment{}, err
+	}
+	return res.Fragments, tombstone, nil
+}
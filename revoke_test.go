@@ -0,0 +1,242 @@
+package horcrux
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+)
+
+func TestRecoverRevokedFragment(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := RevokeFragment(frags[1], priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if frags[1].Revoked {
+		t.Fatal("Revocation modified the original fragment")
+	}
+
+	answers := []Answer{
+		{Fragment: frags[0], Answer: questions[frags[0].Question]},
+		{Fragment: revoked, Answer: questions[revoked.Question]},
+	}
+
+	s, err := Recover(answers, WithRevocationKey(pub))
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	expected := ErrFragmentRevoked{ID: int(revoked.ID)}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRecoverForgedRevocation(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := RevokeFragment(frags[1], other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		{Fragment: frags[0], Answer: questions[frags[0].Question]},
+		{Fragment: revoked, Answer: questions[revoked.Question]},
+	}
+
+	_, err = Recover(answers, WithRevocationKey(pub))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	if _, ok := err.(ErrFragmentRevoked); ok {
+		t.Fatalf("Expected a bad signature error but was %v", err)
+	}
+}
+
+func TestRecoverWithRevocationList(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := RevokeFragment(frags[1], priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the holder clears the flag, or uses their unrevoked copy
+	cleared := revoked
+	cleared.Revoked = false
+
+	for _, f := range []Fragment{frags[1], cleared} {
+		answers := []Answer{
+			frags[0].WithAnswer(questions[frags[0].Question]),
+			f.WithAnswer(questions[f.Question]),
+		}
+
+		if _, err := Recover(answers); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Recover(answers, WithRevocationKey(pub),
+			WithRevocationList([]Fragment{revoked}))
+
+		expected := ErrFragmentRevoked{ID: int(f.ID)}
+		if err != expected {
+			t.Fatalf("Expected %v but was %v", expected, err)
+		}
+	}
+}
+
+func TestRecoverWithForgedRevocationList(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, other, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := RevokeFragment(frags[1], other)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	_, err = Recover(answers, WithRevocationKey(pub),
+		WithRevocationList([]Fragment{revoked}))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	if _, ok := err.(ErrFragmentRevoked); ok {
+		t.Fatalf("Expected a bad signature error but was %v", err)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := int(frags[1].ID)
+	var answers []Answer
+	for _, f := range frags {
+		if int(f.ID) != id {
+			answers = append(answers, f.WithAnswer(questions[f.Question]))
+		}
+	}
+
+	resplit, tombstone, err := Revoke(frags, id, answers, priv,
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resplit) != len(frags)-1 {
+		t.Fatalf("Expected %v but was %v", len(frags)-1, len(resplit))
+	}
+
+	for _, f := range resplit {
+		if f.Question == frags[1].Question {
+			t.Fatalf("Expected question %q to be removed", f.Question)
+		}
+	}
+
+	err = checkRevocation(tombstone, pub)
+	if err != (ErrFragmentRevoked{ID: id}) {
+		t.Fatalf("Expected %v but was %v", ErrFragmentRevoked{ID: id}, err)
+	}
+
+	s, err := Recover(allAnswers(resplit)[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestRevokeMissingAnswer(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := allAnswers(frags)[1:3]
+	if _, _, err := Revoke(frags, int(frags[1].ID), answers, priv); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestRevokeTooFewRemaining(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 4, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = Revoke(frags, int(frags[0].ID), allAnswers(frags), priv)
+
+	expected := ErrTooFewAnswers{Need: 4, Have: 3}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
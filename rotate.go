@@ -0,0 +1,122 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RotateAnswer decrypts the share in the given fragment with the old answer and
+// re-encrypts it for the new answer, with a new salt and nonce, using the given
+// options. Only this one fragment changes, so a compromised answer can be
+// replaced without gathering K answers. The fragment's KDF and its parameters,
+// question, answer hint, normalizer, locale, answer separator, set ID, group,
+// whether it's required, attempt limit, and metadata are kept unless
+// overridden. Alternate answers, escrowed answers, and HMACs are for the old
+// answer, and are not kept.
+func RotateAnswer(f Fragment, oldAnswer, newAnswer string, opts ...SplitOption) (Fragment, error) {
+	if f.QuestionIsHashed {
+		return f, errors.New("horcrux: cannot rotate the answer of a fragment with a private question")
+	}
+
+	cfg, err := fragmentSplitConfig(f)
+	if err != nil {
+		return f, err
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return f, err
+	}
+
+	normalize, err := cfg.normalizer()
+	if err != nil {
+		return f, err
+	}
+
+	normalized := newAnswer
+	if normalize != nil {
+		normalized = normalize(newAnswer)
+	}
+
+	if err := checkAnswerLengths(map[string]string{f.Question: newAnswer},
+		normalize, cfg.minAnswerLength); err != nil {
+		return f, err
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	// a custom AEAD given to encrypt the new share also decrypts the old one
+	var rcfg recoverConfig
+	if cfg.aead != nil {
+		rcfg.aeads = map[string]AEADFunc{cfg.aeadName: cfg.aead}
+	}
+
+	s, err := openShare(ctx, f.WithAnswer(oldAnswer), &rcfg)
+	if err != nil {
+		return f, err
+	}
+	defer zero(s.value)
+
+	var u Fragment
+	err = encryptShare(ctx, &u, f.ID, f.K, f.Question, normalized, newAnswer,
+		s.value, &cfg)
+	if err != nil {
+		return f, err
+	}
+
+	u.MaxAttempts = f.MaxAttempts
+	u.Metadata = f.Clone().Metadata
+	return u, nil
+}
+
+// fragmentSplitConfig returns a split configuration which re-encrypts shares
+// the same way as the given fragment.
+func fragmentSplitConfig(f Fragment) (splitConfig, error) {
+	cfg := defaultSplitConfig()
+	cfg.normalizerID = f.NormalizerID
+	cfg.locale = f.Locale
+	cfg.setID = f.SetID
+	cfg.answerSeparator = f.AnswerSeparator
+	if f.AnswerHint != "" {
+		cfg.hints = map[string]string{f.Question: f.AnswerHint}
+	}
+	if f.Weight > 0 {
+		cfg.weights = map[string]int{f.Question: f.Weight}
+	}
+	cfg.group = f.Group
+	cfg.groupThreshold = f.GroupThreshold
+	if f.Required {
+		cfg.requiredQuestion = f.Question
+	}
+
+	alg, _ := parseKDF(f.KDF)
+	switch alg {
+	case KDFScrypt:
+		WithScryptParams(f.N, f.R, f.P)(&cfg)
+	case KDFBcrypt:
+		WithBcrypt(f.BcryptCost)(&cfg)
+	case KDFArgon2id:
+		WithArgon2id(f.Argon2Time, f.Argon2Memory, f.Argon2Threads)(&cfg)
+	case KDFKey:
+		return cfg, errors.New("horcrux: cannot rotate the key of an extra fragment")
+	default:
+		fn, ok := LookupKDF(alg)
+		if !ok {
+			return cfg, fmt.Errorf("horcrux: unknown KDF %q", alg)
+		}
+
+		kdf, err := fn(f.KDFParams)
+		if err != nil {
+			return cfg, err
+		}
+		WithKDF(kdf)(&cfg)
+	}
+	cfg.kdf = f.KDF
+	if cfg.kdf == "" {
+		cfg.kdf = KDFScrypt
+	}
+	return cfg, nil
+}
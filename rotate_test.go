@@ -0,0 +1,96 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRotateAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.MaxAttempts = 3
+	f.Metadata = map[string]string{"owner": "alice"}
+
+	r, err := RotateAnswer(f, questions[f.Question], "Rover")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.N != f.N || r.R != f.R || r.P != f.P {
+		t.Fatalf("Expected %v/%v/%v but was %v/%v/%v", f.N, f.R, f.P, r.N, r.R, r.P)
+	}
+
+	if bytes.Equal(r.Salt, f.Salt) || bytes.Equal(r.Nonce, f.Nonce) {
+		t.Fatal("Expected a new salt and nonce")
+	}
+
+	if r.MaxAttempts != f.MaxAttempts || r.Metadata["owner"] != "alice" {
+		t.Fatalf("Expected %v but was %v", f, r)
+	}
+
+	if err := VerifyAnswer(r.WithAnswer(questions[f.Question])); err == nil {
+		t.Fatal("Expected the old answer to be wrong")
+	}
+
+	actual, err := Recover([]Answer{
+		r.WithAnswer("rover"),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestRotateAnswerWrongAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = RotateAnswer(frags[0], "wrong", "Rover")
+	if !isAuthFailure(err) {
+		t.Fatalf("Expected authentication failure but was %v", err)
+	}
+}
+
+func TestRotateAnswerBcrypt(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0, WithBcrypt(MinBcryptCost))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	r, err := RotateAnswer(f, questions[f.Question], "Rover")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.KDF != f.KDF || r.BcryptCost != f.BcryptCost {
+		t.Fatalf("Expected %v/%v but was %v/%v", f.KDF, f.BcryptCost, r.KDF,
+			r.BcryptCost)
+	}
+
+	if err := VerifyAnswer(r.WithAnswer("Rover")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotateAnswerPrivateQuestion(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1, WithPrivateQuestions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = RotateAnswer(frags[0], "Spot", "Rover")
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
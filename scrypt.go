@@ -0,0 +1,62 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/codahale/chacha20"
+)
+
+// ScryptParams are the parameters for deriving keys with scrypt.
+type ScryptParams struct {
+	N      int // N is the iteration parameter.
+	R      int // R is the memory parameter.
+	P      int // P is the parallelism parameter.
+	KeyLen int // KeyLen is the length of the derived key, in bytes.
+}
+
+// Validate returns an error if the parameters cannot be used to derive keys.
+func (p ScryptParams) Validate() error {
+	if p.N < MinScryptN || p.N&(p.N-1) != 0 {
+		return errors.New("horcrux: scrypt N must be > 1 and a power of 2")
+	}
+
+	if p.R <= 0 || p.P <= 0 || uint64(p.R)*uint64(p.P) >= 1<<30 {
+		return fmt.Errorf("horcrux: bad scrypt parameters R=%d, P=%d", p.R, p.P)
+	}
+
+	if p.KeyLen != chacha20.KeySize {
+		return fmt.Errorf("horcrux: scrypt key length must be %d",
+			chacha20.KeySize)
+	}
+
+	return nil
+}
+
+// DefaultScryptParams are the scrypt parameters used when none are given. Use
+// SetDefaultScryptParams to change them.
+var DefaultScryptParams = ScryptParams{N: 2 << 14, R: 8, P: 1, KeyLen: 32}
+
+var defaultScryptMu sync.RWMutex
+
+// SetDefaultScryptParams validates the given parameters and, if they are valid,
+// makes them the default.
+func SetDefaultScryptParams(p ScryptParams) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	defaultScryptMu.Lock()
+	defer defaultScryptMu.Unlock()
+
+	DefaultScryptParams = p
+	return nil
+}
+
+func defaultScryptParams() ScryptParams {
+	defaultScryptMu.RLock()
+	defer defaultScryptMu.RUnlock()
+
+	return DefaultScryptParams
+}
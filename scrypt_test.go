@@ -0,0 +1,62 @@
+package horcrux
+
+import "testing"
+
+func TestSplitDefaultScryptParams(t *testing.T) {
+	old := DefaultScryptParams
+	defer func() { DefaultScryptParams = old }()
+
+	p := ScryptParams{N: 2 << 9, R: 4, P: 2, KeyLen: 32}
+	if err := SetDefaultScryptParams(p); err != nil {
+		t.Fatal(err)
+	}
+
+	frags, err := Split(secret, questions, 2, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.N != p.N || f.R != p.R || f.P != p.P {
+			t.Fatalf("Expected %v but was %d/%d/%d", p, f.N, f.R, f.P)
+		}
+	}
+}
+
+func TestSetDefaultScryptParamsInvalid(t *testing.T) {
+	old := DefaultScryptParams
+
+	err := SetDefaultScryptParams(ScryptParams{N: 7, R: 8, P: 1, KeyLen: 32})
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	expected := "horcrux: scrypt N must be > 1 and a power of 2"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+
+	if DefaultScryptParams != old {
+		t.Fatalf("Expected %v but was %v", old, DefaultScryptParams)
+	}
+}
+
+func TestScryptParamsValidate(t *testing.T) {
+	bad := []ScryptParams{
+		{N: 0, R: 8, P: 1, KeyLen: 32},
+		{N: 1024, R: 0, P: 1, KeyLen: 32},
+		{N: 1024, R: 8, P: 0, KeyLen: 32},
+		{N: 1024, R: 8, P: 1, KeyLen: 16},
+	}
+
+	for _, p := range bad {
+		if err := p.Validate(); err == nil {
+			t.Errorf("Expected %v to be invalid", p)
+		}
+	}
+
+	if err := DefaultScryptParams.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,84 @@
+package horcrux
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const masterKeyLen = 32
+
+// sealMagic identifies a sealed fragment set, and is authenticated as
+// associated data.
+var sealMagic = []byte("HORCRUX-SEALED-SET-1\n")
+
+// SealFragmentSet encrypts the JSON encoding of the fragment set with
+// AES-256-GCM under the given 256-bit master key. The result is a magic header,
+// a random nonce, and the ciphertext.
+func SealFragmentSet(fs FragmentSet, masterKey []byte) ([]byte, error) {
+	aead, err := newMasterAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(fs)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(b)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(append([]byte{}, sealMagic...), nonce...)
+	return aead.Seal(out, nonce, b, sealMagic), nil
+}
+
+// OpenFragmentSet decrypts a fragment set sealed with SealFragmentSet.
+func OpenFragmentSet(sealed, masterKey []byte) (FragmentSet, error) {
+	aead, err := newMasterAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(sealed, sealMagic) {
+		return nil, errors.New("horcrux: not a sealed fragment set")
+	}
+	sealed = sealed[len(sealMagic):]
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errTruncated
+	}
+
+	b, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], sealMagic)
+	if err != nil {
+		return nil, fmt.Errorf("horcrux: cannot open fragment set: %w", err)
+	}
+	defer zero(b)
+
+	var fs FragmentSet
+	if err := json.Unmarshal(b, &fs); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func newMasterAEAD(masterKey []byte) (cipher.AEAD, error) {
+	if len(masterKey) != masterKeyLen {
+		return nil, fmt.Errorf("horcrux: master key must be %d bytes",
+			masterKeyLen)
+	}
+
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
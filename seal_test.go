@@ -0,0 +1,61 @@
+package horcrux
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSealFragmentSet(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := bytes.Repeat([]byte{1}, 32)
+
+	sealed, err := SealFragmentSet(frags, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(sealed, sealMagic) {
+		t.Fatalf("Expected magic header but was %q", sealed[:len(sealMagic)])
+	}
+
+	actual, err := OpenFragmentSet(sealed, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, FragmentSet(frags)) {
+		t.Fatalf("Expected %v but was %v", frags, actual)
+	}
+}
+
+func TestOpenFragmentSetWrongKey(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := SealFragmentSet(frags, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = OpenFragmentSet(sealed, bytes.Repeat([]byte{2}, 32))
+	if err == nil || !strings.Contains(err.Error(), "message authentication failed") {
+		t.Fatalf("Expected authentication error but was %v", err)
+	}
+}
+
+func TestOpenFragmentSetNotSealed(t *testing.T) {
+	_, err := OpenFragmentSet([]byte("nope"), bytes.Repeat([]byte{1}, 32))
+
+	expected := "horcrux: not a sealed fragment set"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
@@ -0,0 +1,59 @@
+package horcrux
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Serialization formats supported by Serialize and UnmarshalFragment.
+const (
+	FormatBinary = "binary" // FormatBinary is the compact binary encoding.
+	FormatJSON   = "json"   // FormatJSON is the JSON encoding.
+	FormatPEM    = "pem"    // FormatPEM is the PEM encoding.
+
+	FormatMessagePack = "msgpack" // FormatMessagePack is the MessagePack encoding.
+	FormatCBOR        = "cbor"    // FormatCBOR is the canonical CBOR encoding.
+)
+
+// ErrUnknownFormat is returned when serializing or deserializing a fragment in
+// an unsupported format.
+var ErrUnknownFormat = errors.New("horcrux: unknown fragment format")
+
+// Serialize encodes the fragment in the given format.
+func (f Fragment) Serialize(format string) ([]byte, error) {
+	switch format {
+	case FormatBinary:
+		return f.MarshalBinary()
+	case FormatJSON:
+		return json.Marshal(f)
+	case FormatPEM:
+		return f.MarshalPEM()
+	case FormatMessagePack:
+		return f.ToMessagePack()
+	case FormatCBOR:
+		return f.ToCBOR()
+	}
+	return nil, ErrUnknownFormat
+}
+
+// UnmarshalFragment decodes a fragment which was encoded in the given format.
+func UnmarshalFragment(format string, data []byte) (Fragment, error) {
+	var f Fragment
+	var err error
+
+	switch format {
+	case FormatBinary:
+		err = f.UnmarshalBinary(data)
+	case FormatJSON:
+		err = json.Unmarshal(data, &f)
+	case FormatPEM:
+		f, err = UnmarshalFragmentPEM(data)
+	case FormatMessagePack:
+		f, err = FragmentFromMessagePack(data)
+	case FormatCBOR:
+		f, err = FragmentFromCBOR(data)
+	default:
+		err = ErrUnknownFormat
+	}
+	return f, err
+}
@@ -0,0 +1,43 @@
+package horcrux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSerializeRoundTrip(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{FormatBinary, FormatJSON, FormatPEM, FormatMessagePack, FormatCBOR} {
+		b, err := frags[0].Serialize(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		actual, err := UnmarshalFragment(format, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, frags[0]) {
+			t.Fatalf("Expected %v but was %v in %s", frags[0], actual, format)
+		}
+	}
+}
+
+func TestSerializeUnknownFormat(t *testing.T) {
+	b, err := Fragment{}.Serialize("xml")
+	if err != ErrUnknownFormat {
+		t.Fatalf("Expected %v but was %v (%v)", ErrUnknownFormat, err, b)
+	}
+}
+
+func TestUnmarshalFragmentUnknownFormat(t *testing.T) {
+	f, err := UnmarshalFragment("xml", nil)
+	if err != ErrUnknownFormat {
+		t.Fatalf("Expected %v but was %v (%v)", ErrUnknownFormat, err, f)
+	}
+}
@@ -0,0 +1,132 @@
+package horcrux
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// A RecoverySession accumulates answers as they arrive, decrypting each one
+// immediately, until enough have been added to recover the secret. It is safe
+// for concurrent use.
+type RecoverySession struct {
+	cfg recoverConfig
+
+	mu     sync.Mutex
+	ready  *sync.Cond
+	k      int
+	setID  []byte
+	shares map[byte]share
+}
+
+// NewRecoverySession returns a new, empty recovery session.
+func NewRecoverySession(opts ...RecoverOption) *RecoverySession {
+	s := &RecoverySession{shares: make(map[byte]share)}
+	for _, opt := range opts {
+		opt(&s.cfg)
+	}
+	s.ready = sync.NewCond(&s.mu)
+	return s
+}
+
+// Add decrypts the answer's share and adds it to the session. Returns an error
+// if the answer is wrong or belongs to a different set of fragments than the
+// answers already added.
+func (s *RecoverySession) Add(answer Answer) error {
+	if err := checkUngrouped([]Answer{answer}); err != nil {
+		return err
+	}
+
+	ctx, cancel := withTimeout(s.cfg.ctx, s.cfg.timeout)
+	defer cancel()
+
+	sh, err := openShare(ctx, answer, &s.cfg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.k != 0 && s.k != answer.K {
+		return fmt.Errorf("horcrux: answer needs %d answers but session needs %d",
+			answer.K, s.k)
+	}
+
+	if s.setID != nil && answer.SetID != nil &&
+		!bytes.Equal(s.setID, answer.SetID) {
+		return ErrMixedSets{IDs: []int{int(answer.ID)}}
+	}
+
+	if _, ok := s.shares[sh.id]; ok {
+		return fmt.Errorf("horcrux: already have an answer for fragment %d",
+			sh.id)
+	}
+
+	s.k = answer.K
+	if s.setID == nil {
+		s.setID = answer.SetID
+	}
+	s.shares[sh.id] = sh
+	s.ready.Broadcast()
+	return nil
+}
+
+// Complete waits until enough answers have been added to the session, then
+// combines them and returns the secret. Complete blocks until then, so it must
+// not be called unless enough answers will be added eventually.
+func (s *RecoverySession) Complete() ([]byte, error) {
+	return s.CompleteContext(context.Background())
+}
+
+// CompleteContext is like Complete, but returns early with the context's error
+// if the context is done before enough answers have been added.
+func (s *RecoverySession) CompleteContext(ctx context.Context) ([]byte, error) {
+	stop := context.AfterFunc(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.ready.Broadcast()
+	})
+	defer stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.k == 0 || s.weight() < s.k {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		s.ready.Wait()
+	}
+
+	return safeCombine(s.sharesLocked(), s.k)
+}
+
+// Destroy zeroes and discards the shares added so far, leaving the session
+// empty, as if it were new.
+func (s *RecoverySession) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, sh := range s.shares {
+		zero(sh.value)
+		delete(s.shares, id)
+	}
+	s.k = 0
+	s.setID = nil
+}
+
+// sharesLocked returns the shares added so far. s.mu must be held.
+func (s *RecoverySession) sharesLocked() []share {
+	shares := make([]share, 0, len(s.shares))
+	for _, sh := range s.shares {
+		shares = append(shares, sh)
+	}
+	return shares
+}
+
+// weight returns the number of shares added so far. s.mu must be held.
+func (s *RecoverySession) weight() int {
+	return sharesWeight(s.sharesLocked())
+}
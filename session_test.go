@@ -0,0 +1,122 @@
+package horcrux
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecoverySession(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewRecoverySession()
+
+	done := make(chan []byte)
+	go func() {
+		secret, err := s.Complete()
+		if err != nil {
+			t.Error(err)
+		}
+		done <- secret
+	}()
+
+	var wg sync.WaitGroup
+	for _, f := range frags[:2] {
+		wg.Add(1)
+		go func(f Fragment) {
+			defer wg.Done()
+			if err := s.Add(f.WithAnswer(questions[f.Question])); err != nil {
+				t.Error(err)
+			}
+		}(f)
+	}
+	wg.Wait()
+
+	select {
+	case actual := <-done:
+		if string(actual) != string(secret) {
+			t.Fatalf("Expected %q but was %q", secret, actual)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Complete did not return")
+	}
+}
+
+func TestRecoverySessionWaits(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewRecoverySession()
+	if err := s.Add(frags[0].WithAnswer(questions[frags[0].Question])); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	actual, err := s.CompleteContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v but was %v (%v)", context.DeadlineExceeded, err, actual)
+	}
+}
+
+func TestRecoverySessionWrongAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewRecoverySession()
+	err = s.Add(frags[0].WithAnswer("wrong"))
+
+	expected := "horcrux: fragment 1: message authentication failed"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRecoverySessionDuplicate(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewRecoverySession()
+	a := frags[0].WithAnswer(questions[frags[0].Question])
+	if err := s.Add(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Add(a); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestRecoverySessionDestroy(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewRecoverySession()
+	if err := s.Add(frags[0].WithAnswer(questions[frags[0].Question])); err != nil {
+		t.Fatal(err)
+	}
+
+	v := s.shares[frags[0].ID].value
+	s.Destroy()
+
+	if len(s.shares) != 0 || s.k != 0 || s.setID != nil {
+		t.Fatal("Expected the session to be empty")
+	}
+
+	if v[0] != 0 || v[len(v)-1] != 0 {
+		t.Fatalf("Expected %x to be zeroed", v)
+	}
+}
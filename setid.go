@@ -0,0 +1,52 @@
+package horcrux
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// setIDSize is the size of a split's random set identifier.
+const setIDSize = 16
+
+// newSetID returns a new random set identifier read from r.
+func newSetID(r io.Reader) ([]byte, error) {
+	id := make([]byte, setIDSize)
+	if _, err := io.ReadFull(r, id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// ErrMixedSets is returned by Recover when the answers' fragments were produced
+// by different splits. Combining shares of different secrets would otherwise
+// silently produce garbage.
+type ErrMixedSets struct {
+	IDs []int // IDs are the IDs of the fragments from a different set.
+}
+
+func (e ErrMixedSets) Error() string {
+	return fmt.Sprintf("horcrux: fragments %v belong to a different set", e.IDs)
+}
+
+// checkSets returns ErrMixedSets if any of the answers' fragments have a
+// different set ID than the first one which has one. Fragments without a set
+// ID, which predate them, are not checked.
+func checkSets(answers []Answer) error {
+	var first []byte
+	var ids []int
+	for _, a := range answers {
+		switch {
+		case a.SetID == nil:
+		case first == nil:
+			first = a.SetID
+		case !bytes.Equal(a.SetID, first):
+			ids = append(ids, int(a.ID))
+		}
+	}
+
+	if ids != nil {
+		return ErrMixedSets{IDs: ids}
+	}
+	return nil
+}
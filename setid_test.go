@@ -0,0 +1,107 @@
+package horcrux
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSplitSetID(t *testing.T) {
+	a, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a[0].SetID) != setIDSize {
+		t.Fatalf("Expected %v but was %v", setIDSize, len(a[0].SetID))
+	}
+
+	for _, f := range a[1:] {
+		if !bytes.Equal(f.SetID, a[0].SetID) {
+			t.Fatalf("Expected %x but was %x", a[0].SetID, f.SetID)
+		}
+	}
+
+	if bytes.Equal(a[0].SetID, b[0].SetID) {
+		t.Fatal("Expected different splits to have different set IDs")
+	}
+}
+
+func TestSplitSetIDExtraShares(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	res, err := SplitWithResult(secret, questions, 2,
+		WithScryptParams(2<<10, 8, 1), WithExtraShares(1), WithExtraShareKey(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range res.ExtraFragments {
+		if !bytes.Equal(f.SetID, res.Fragments[0].SetID) {
+			t.Fatalf("Expected %x but was %x", res.Fragments[0].SetID, f.SetID)
+		}
+	}
+}
+
+func TestRecoverEarlyMixedSets(t *testing.T) {
+	a, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = RecoverEarly([]Answer{
+		a[0].WithAnswer(questions[a[0].Question]),
+		b[1].WithAnswer(questions[b[1].Question]),
+	})
+
+	expected := ErrMixedSets{IDs: []int{int(b[1].ID)}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRecoverySessionMixedSets(t *testing.T) {
+	a, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewRecoverySession()
+	if err := s.Add(a[0].WithAnswer(questions[a[0].Question])); err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Add(b[1].WithAnswer(questions[b[1].Question]))
+
+	expected := ErrMixedSets{IDs: []int{int(b[1].ID)}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestCheckSetsWithoutSetIDs(t *testing.T) {
+	answers := []Answer{
+		{Fragment: Fragment{ID: 1}},
+		{Fragment: Fragment{ID: 2, SetID: []byte{1}}},
+		{Fragment: Fragment{ID: 3}},
+		{Fragment: Fragment{ID: 4, SetID: []byte{1}}},
+	}
+
+	if err := checkSets(answers); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,140 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/codahale/sss"
+)
+
+// share is a decrypted Shamir share. A share decrypted from a weighted fragment
+// holds n shares, with consecutive IDs, concatenated.
+type share struct {
+	id    byte
+	value []byte
+	n     int
+}
+
+// ErrCombineFailed is returned when shares cannot be combined into a secret,
+// e.g. because they come from splits of secrets of different lengths.
+type ErrCombineFailed struct {
+	Shares int // Shares is the number of shares given.
+	K      int // K is the number of shares required.
+}
+
+func (e ErrCombineFailed) Error() string {
+	return fmt.Sprintf("horcrux: cannot combine %d shares (need %d)",
+		e.Shares, e.K)
+}
+
+// ErrEmptyRecoveredSecret is returned when shares combine into an empty secret.
+var ErrEmptyRecoveredSecret = errors.New("horcrux: recovered secret is empty")
+
+// safeCombine combines the shares like combine, but returns ErrCombineFailed
+// instead of a nil or garbled secret if there are fewer than k distinct share
+// IDs or the shares aren't all the same length, and ErrEmptyRecoveredSecret if
+// the secret is empty. Shares from different splits of secrets of the same
+// length can't be detected, and combine into garbage.
+func safeCombine(shares []share, k int) ([]byte, error) {
+	shares = expandShares(shares)
+	if n := sharesWeight(shares); n == 0 || n < k {
+		return nil, ErrCombineFailed{Shares: n, K: k}
+	}
+
+	for _, s := range shares[1:] {
+		if len(s.value) != len(shares[0].value) {
+			return nil, ErrCombineFailed{Shares: len(shares), K: k}
+		}
+	}
+
+	secret := combine(shares)
+	if secret == nil {
+		return nil, ErrCombineFailed{Shares: len(shares), K: k}
+	}
+
+	if len(secret) == 0 {
+		return nil, ErrEmptyRecoveredSecret
+	}
+	return secret, nil
+}
+
+// combine combines the given shares into the secret. The shares are sorted by
+// ID, so that the order in which answers are given doesn't affect which of
+// several shares with the same ID is used: the last one given. This is not a
+// timing defense: sss.Combine only accepts a map keyed by share ID, and its
+// GF(2^8) arithmetic uses table lookups indexed by share bytes and IDs, so
+// combining shares is not constant-time.
+func combine(shares []share) []byte {
+	sort.SliceStable(shares, func(i, j int) bool {
+		return shares[i].id < shares[j].id
+	})
+
+	m := make(map[byte][]byte, len(shares))
+	for _, s := range shares {
+		m[s.id] = s.value
+	}
+	return sss.Combine(m)
+}
+
+// EncryptShares encrypts pre-computed Shamir shares, such as shares generated
+// by a hardware security module, based on the given security questions. Each
+// question is assigned one share; share IDs must be between 1 and 255. k is
+// recorded as the number of shares required to recover the secret. Unless
+// overridden with WithScryptParams, the recommended scrypt parameters are
+// used. Returns either a slice of fragments or an error.
+func EncryptShares(shares map[int][]byte, questions map[string]string, k int, opts ...SplitOption) ([]Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	questions, err := normalizeQuestions(questions)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[byte][]byte, len(shares))
+	for id, v := range shares {
+		if id < 1 || id > 255 {
+			return nil, fmt.Errorf("horcrux: bad share ID %d", id)
+		}
+		m[byte(id)] = v
+	}
+
+	res, err := encryptShares(m, questions, k, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return res.Fragments, nil
+}
+
+// DecryptShares decrypts the shares of the given answers without combining
+// them. Returns either a map of share IDs to shares or an error.
+func DecryptShares(answers []Answer, opts ...RecoverOption) (map[int][]byte, error) {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// shares of different groups can have the same ID
+	if err := checkUngrouped(answers); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	shares := make(map[int][]byte, len(answers))
+	for _, a := range answers {
+		s, err := openShare(ctx, a, &cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range expandShares([]share{s}) {
+			shares[int(p.id)] = p.value
+		}
+	}
+	return shares, nil
+}
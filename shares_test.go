@@ -0,0 +1,235 @@
+package horcrux
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCombineOrder(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 3)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	for _, order := range [][]int{{0, 1, 2}, {2, 0, 1}, {1, 2, 0}} {
+		shuffled := make([]Answer, len(order))
+		for i, j := range order {
+			shuffled[i] = answers[j]
+		}
+
+		s, err := Recover(shuffled)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(s) != string(secret) {
+			t.Fatalf("Expected %q but was %q", secret, s)
+		}
+	}
+}
+
+func TestRecoverConcurrent(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := Recover(answers)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestEncryptDecryptShares(t *testing.T) {
+	shares := map[int][]byte{
+		3:  []byte("three"),
+		7:  []byte("seven"),
+		9:  []byte("nine"),
+		12: []byte("twelve"),
+	}
+
+	frags, err := EncryptShares(shares, questions, 2, WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, len(frags))
+	for i, f := range frags {
+		answers[i] = f.WithAnswer(questions[f.Question])
+	}
+
+	actual, err := DecryptShares(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, shares) {
+		t.Fatalf("Expected %v but was %v", shares, actual)
+	}
+}
+
+func TestEncryptSharesBadID(t *testing.T) {
+	frags, err := EncryptShares(map[int][]byte{0: {1}}, map[string]string{"Q": "A"}, 2)
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := "horcrux: bad share ID 0"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestEncryptSharesCountMismatch(t *testing.T) {
+	frags, err := EncryptShares(map[int][]byte{1: {1}}, questions, 2)
+	if err == nil {
+		t.Fatalf("Expected error but got %v", frags)
+	}
+
+	expected := "horcrux: have 1 shares but 4 questions"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestRecoverDifferentSplits(t *testing.T) {
+	a, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Split([]byte("a different, longer secret"), questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		a[0].WithAnswer(questions[a[0].Question]),
+		b[1].WithAnswer(questions[b[1].Question]),
+	}
+
+	s, err := Recover(answers)
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	expected := ErrMixedSets{IDs: []int{int(b[1].ID)}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRecoverDifferentSplitsWithoutSetIDs(t *testing.T) {
+	a, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Split([]byte("a different, longer secret"), questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// fragments which predate set IDs can only be caught when combining
+	answers := []Answer{
+		a[0].WithAnswer(questions[a[0].Question]),
+		b[1].WithAnswer(questions[b[1].Question]),
+	}
+	for i, a := range answers {
+		answers[i].Fragment = unbind(t, a.Fragment, a.Answer)
+		answers[i].SetID = nil
+		answers[i].StructureChecksum = nil
+	}
+
+	s, err := Recover(answers)
+	if s != nil {
+		t.Fatalf("Expected nil, but was %v", s)
+	}
+
+	expected := ErrCombineFailed{Shares: 2, K: 2}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestSafeCombineEmptySecret(t *testing.T) {
+	shares := []share{{id: 1, value: []byte{}}, {id: 2, value: []byte{}}}
+
+	if _, err := safeCombine(shares, 2); err != ErrEmptyRecoveredSecret {
+		t.Fatalf("Expected %v but was %v", ErrEmptyRecoveredSecret, err)
+	}
+}
+
+func TestSafeCombineTooFewShares(t *testing.T) {
+	_, err := safeCombine(nil, 2)
+
+	expected := "horcrux: cannot combine 0 shares (need 2)"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
+
+func TestRecoverDuplicateAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := frags[0].WithAnswer(questions[frags[0].Question])
+	expected := ErrTooFewAnswers{Need: 2, Have: 1}
+
+	s, err := Recover([]Answer{a, a})
+	if s != nil || err != expected {
+		t.Fatalf("Expected %v but was %v, %v", expected, s, err)
+	}
+
+	s, err = RecoverEarly([]Answer{a, a})
+	if s != nil || err != expected {
+		t.Fatalf("Expected %v but was %v, %v", expected, s, err)
+	}
+
+	res, err := RecoverPartial([]Answer{a, a})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Secret != nil || res.RemainingNeeded != 1 {
+		t.Fatalf("Expected 1 more share but was %+v", res)
+	}
+}
+
+func TestSafeCombineDuplicateIDs(t *testing.T) {
+	shares := []share{{id: 1, value: []byte{1, 2}}, {id: 1, value: []byte{1, 2}}}
+
+	expected := ErrCombineFailed{Shares: 1, K: 2}
+	if _, err := safeCombine(shares, 2); err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
@@ -0,0 +1,48 @@
+package horcrux
+
+import "fmt"
+
+// ErrInvalidFragmentSize is returned when a field of a fragment does not have
+// the length it should.
+type ErrInvalidFragmentSize struct {
+	ID       int    // ID is the ID of the fragment.
+	Field    string // Field is the name of the field with the wrong length.
+	Expected int    // Expected is the length the field should have.
+	Actual   int    // Actual is the length the field has.
+}
+
+func (e ErrInvalidFragmentSize) Error() string {
+	return fmt.Sprintf("horcrux: fragment %d has a %d-byte %s, expected %d",
+		e.ID, e.Actual, e.Field, e.Expected)
+}
+
+// ValidateSize checks that the fragment's nonce, salt, and encrypted shares all
+// have the lengths expected for a secret of the given length, allowing for the
+// fragment's weight. Fragments read from untrusted sources should be validated
+// before being used for recovery, as an oversized value would otherwise be
+// processed in full. The expected lengths are those of ChaCha20Poly1305, so
+// fragments encrypted with a custom AEAD (see WithSplitAEAD) may not validate.
+func (f Fragment) ValidateSize(expectedSecretLen int) error {
+	checks := []struct {
+		field    string
+		expected int
+		actual   int
+	}{
+		{"value", expectedSecretLen*f.weight() + Overhead, len(f.Value)},
+		{"nonce", NonceSize, len(f.Nonce)},
+		{"salt", SaltSize, len(f.Salt)},
+	}
+
+	for _, c := range checks {
+		if c.actual != c.expected {
+			return ErrInvalidFragmentSize{
+				ID:       int(f.ID),
+				Field:    c.field,
+				Expected: c.expected,
+				Actual:   c.actual,
+			}
+		}
+	}
+
+	return nil
+}
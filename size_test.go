@@ -0,0 +1,88 @@
+package horcrux
+
+import "testing"
+
+func TestValidateSize(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if err := f.ValidateSize(len(secret)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestValidateSizeWeighted(t *testing.T) {
+	frags, err := SplitWithOptions(secret, questions, 3,
+		WithScryptParams(2<<10, 8, 1),
+		WithWeights(map[string]int{"What's your real name?": 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weighted := 0
+	for _, f := range frags {
+		if f.Weight == 2 {
+			weighted++
+		}
+
+		if err := f.ValidateSize(len(secret)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if weighted != 1 {
+		t.Fatalf("Expected %v but was %v", 1, weighted)
+	}
+}
+
+func TestValidateSizeOversizedValue(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	derived := false
+	old := derive
+	defer func() { derive = old }()
+	derive = func(f Fragment, answer string) ([]byte, error) {
+		derived = true
+		return old(f, answer)
+	}
+
+	f := frags[0]
+	f.Value = make([]byte, 1<<20)
+
+	err = f.ValidateSize(len(secret))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	expected := "horcrux: fragment 1 has a 1048576-byte value, expected 36"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+
+	if derived {
+		t.Fatal("Expected no key derivation but one was attempted")
+	}
+}
+
+func TestValidateSizeBadSalt(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.Salt = f.Salt[:4]
+
+	err = f.ValidateSize(len(secret))
+	if e, ok := err.(ErrInvalidFragmentSize); !ok || e.Field != "salt" {
+		t.Fatalf("Expected salt size error but was %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SecretSplitter builds a set of fragments step by step, such as when
+// questions are added one at a time in an interactive UI. The zero value is
+// ready to use. Answers are kept only until Build is called, after which the
+// splitter's copies of the secret and answers are zeroed and it must be
+// configured again.
+type SecretSplitter struct {
+	secret  []byte
+	answers map[string][]byte
+	k       int
+	opts    []SplitOption
+}
+
+// SetSecret sets the secret to be split. The secret is copied.
+func (s *SecretSplitter) SetSecret(secret []byte) {
+	zero(s.secret)
+	s.secret = append([]byte{}, secret...)
+}
+
+// AddQuestion adds a security question and its answer, returning an error if
+// the question has already been added.
+func (s *SecretSplitter) AddQuestion(q, a string) error {
+	q = norm.NFC.String(q)
+	if _, ok := s.answers[q]; ok {
+		return fmt.Errorf("horcrux: duplicate question %q", q)
+	}
+
+	if s.answers == nil {
+		s.answers = make(map[string][]byte)
+	}
+	s.answers[q] = []byte(a)
+	return nil
+}
+
+// SetK sets the number of fragments required to recover the secret.
+func (s *SecretSplitter) SetK(k int) {
+	s.k = k
+}
+
+// SetOptions sets the options used to split the secret, replacing any which
+// were set before.
+func (s *SecretSplitter) SetOptions(opts ...SplitOption) {
+	s.opts = opts
+}
+
+// Build checks that the secret, questions, and K are all set, and splits the
+// secret like SplitWithResult.
+func (s *SecretSplitter) Build() ([]Fragment, error) {
+	defer s.reset()
+
+	if s.secret == nil {
+		return nil, errors.New("horcrux: no secret set")
+	}
+
+	if s.k == 0 {
+		return nil, errors.New("horcrux: no K set")
+	}
+
+	if s.k > len(s.answers) {
+		return nil, fmt.Errorf("horcrux: K is %d but only have %d questions",
+			s.k, len(s.answers))
+	}
+
+	questions := make(map[string]string, len(s.answers))
+	for q, a := range s.answers {
+		questions[q] = string(a)
+	}
+
+	res, err := SplitWithResult(s.secret, questions, s.k, s.opts...)
+	if err != nil {
+		return nil, err
+	}
+	return res.Fragments, nil
+}
+
+// Destroy zeroes and discards the secret and answers, as Build does.
+func (s *SecretSplitter) Destroy() {
+	s.reset()
+}
+
+// reset zeroes and discards the secret and answers.
+func (s *SecretSplitter) reset() {
+	zero(s.secret)
+	s.secret = nil
+
+	for _, a := range s.answers {
+		zero(a)
+	}
+	s.answers = nil
+}
@@ -0,0 +1,126 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSecretSplitter(t *testing.T) {
+	var s SecretSplitter
+	s.SetOptions(WithScryptParams(2<<10, 8, 1))
+	s.SetK(2)
+	for q, a := range questions {
+		if err := s.AddQuestion(q, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.SetSecret(secret)
+
+	frags, err := s.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		frags[1].WithAnswer(questions[frags[1].Question]),
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+
+	if s.secret != nil || s.answers != nil {
+		t.Fatal("Expected the secret and answers to be discarded")
+	}
+}
+
+func TestSecretSplitterDuplicateQuestion(t *testing.T) {
+	var s SecretSplitter
+	if err := s.AddQuestion("Pet?", "Spot"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.AddQuestion("Pet?", "Rover")
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	expected := `horcrux: duplicate question "Pet?"`
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestSecretSplitterIncomplete(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		build    func(s *SecretSplitter)
+		expected string
+	}{
+		{
+			"no secret",
+			func(s *SecretSplitter) {
+				s.SetK(2)
+				_ = s.AddQuestion("A?", "a")
+				_ = s.AddQuestion("B?", "b")
+			},
+			"horcrux: no secret set",
+		},
+		{
+			"no K",
+			func(s *SecretSplitter) {
+				s.SetSecret(secret)
+				_ = s.AddQuestion("A?", "a")
+				_ = s.AddQuestion("B?", "b")
+			},
+			"horcrux: no K set",
+		},
+		{
+			"too few questions",
+			func(s *SecretSplitter) {
+				_ = s.AddQuestion("A?", "a")
+				s.SetK(2)
+				s.SetSecret(secret)
+			},
+			"horcrux: K is 2 but only have 1 questions",
+		},
+	} {
+		var s SecretSplitter
+		test.build(&s)
+
+		_, err := s.Build()
+		if err == nil {
+			t.Fatalf("%s: Expected error but got none", test.name)
+		}
+
+		if actual := err.Error(); actual != test.expected {
+			t.Fatalf("%s: Expected %v but was %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestSecretSplitterDestroy(t *testing.T) {
+	var s SecretSplitter
+	s.SetSecret(secret)
+	if err := s.AddQuestion("What's your real name?", "Rumplestiltskin"); err != nil {
+		t.Fatal(err)
+	}
+
+	v := s.secret
+	s.Destroy()
+
+	if s.secret != nil || s.answers != nil {
+		t.Fatal("Expected the secret and answers to be discarded")
+	}
+
+	if !bytes.Equal(v, make([]byte, len(v))) {
+		t.Fatalf("Expected %x to be zeroed", v)
+	}
+}
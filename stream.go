@@ -0,0 +1,140 @@
+package horcrux
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/codahale/chacha20"
+	"github.com/codahale/chacha20poly1305"
+)
+
+// streamChunkSize is the size of each chunk of plaintext in an encrypted
+// stream. Every chunk but the last is exactly this size.
+const streamChunkSize = 64 << 10
+
+// ErrCorruptStream is returned by RecoverReader when the encrypted stream has
+// been modified, truncated, or was encrypted with a different data key.
+var ErrCorruptStream = errors.New("horcrux: encrypted stream is corrupt")
+
+// SplitReader splits a secret which is too large to hold in memory, such as a
+// disk image. It generates a random 256-bit data key, encrypts everything read
+// from r with it using ChaCha20Poly1305 in 64KiB chunks, writes the ciphertext
+// to w, and returns fragments of the data key split like SplitWithOptions.
+// RecoverReader reverses this.
+func SplitReader(r io.Reader, w io.Writer, questions map[string]string, k int, opts ...SplitOption) ([]Fragment, error) {
+	cfg := defaultSplitConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	dek := make([]byte, chacha20.KeySize)
+	defer zero(dek)
+
+	if _, err := io.ReadFull(cfg.random(), dek); err != nil {
+		return nil, err
+	}
+
+	frags, err := SplitWithOptions(dek, questions, k, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	seal := func(dst, nonce, chunk []byte) ([]byte, error) {
+		return aead.Seal(dst, nonce, chunk, nil), nil
+	}
+
+	if _, err := streamChunks(r, w, streamChunkSize, aead.NonceSize(), seal); err != nil {
+		return nil, err
+	}
+	return frags, nil
+}
+
+// RecoverReader recovers the data key from the given answers like Recover, and
+// uses it to decrypt the stream read from r, which was written by SplitReader,
+// writing the plaintext to w. Chunks are written as they are authenticated, so
+// if an error is returned, anything already written to w must be discarded.
+// Returns the number of bytes written.
+func RecoverReader(answers []Answer, r io.Reader, w io.Writer, opts ...RecoverOption) (int64, error) {
+	dek, err := Recover(answers, opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer zero(dek)
+
+	if len(dek) != chacha20.KeySize {
+		return 0, fmt.Errorf("horcrux: recovered a %d-byte data key", len(dek))
+	}
+
+	aead, err := chacha20poly1305.New(dek)
+	if err != nil {
+		return 0, err
+	}
+
+	open := func(dst, nonce, chunk []byte) ([]byte, error) {
+		out, err := aead.Open(dst, nonce, chunk, nil)
+		if err != nil {
+			return nil, ErrCorruptStream
+		}
+		return out, nil
+	}
+
+	return streamChunks(r, w, streamChunkSize+aead.Overhead(),
+		aead.NonceSize(), open)
+}
+
+// streamChunks reads r in chunks of the given size, transforms each with fn,
+// and writes the results to w, returning the number of bytes written. The last
+// eight bytes of each chunk's nonce are its index, with the top bit set for the last chunk, so that
+// chunks can't be reordered, dropped, or truncated undetected.
+func streamChunks(r io.Reader, w io.Writer, size, nonceSize int, fn func(dst, nonce, chunk []byte) ([]byte, error)) (int64, error) {
+	br := bufio.NewReaderSize(r, size)
+	buf := make([]byte, size)
+	nonce := make([]byte, nonceSize)
+	counter := nonce[nonceSize-8:]
+	var out []byte
+	var written int64
+
+	for i := uint64(0); ; i++ {
+		n, err := io.ReadFull(br, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return written, err
+		}
+
+		last := err != nil
+		if !last {
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return written, err
+			}
+		}
+
+		binary.BigEndian.PutUint64(counter, i)
+		if last {
+			counter[0] |= 0x80
+		}
+
+		out, err = fn(out[:0], nonce, buf[:n])
+		if err != nil {
+			return written, err
+		}
+
+		m, err := w.Write(out)
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+
+		if last {
+			return written, nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package horcrux
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+)
+
+func splitStream(t *testing.T, plaintext []byte) ([]Answer, []byte) {
+	var ciphertext bytes.Buffer
+	frags, err := SplitReader(bytes.NewReader(plaintext), &ciphertext,
+		questions, 2, WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer(questions[frags[i].Question])
+	}
+	return answers, ciphertext.Bytes()
+}
+
+func TestSplitReader(t *testing.T) {
+	for _, size := range []int{0, 100, streamChunkSize, 3*streamChunkSize + 5} {
+		plaintext := make([]byte, size)
+		if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+			t.Fatal(err)
+		}
+
+		answers, ciphertext := splitStream(t, plaintext)
+
+		var actual bytes.Buffer
+		n, err := RecoverReader(answers, bytes.NewReader(ciphertext), &actual)
+		if err != nil {
+			t.Fatalf("%d bytes: %v", size, err)
+		}
+
+		if n != int64(size) {
+			t.Fatalf("Expected %v but was %v", size, n)
+		}
+
+		if !bytes.Equal(actual.Bytes(), plaintext) {
+			t.Fatalf("%d bytes: plaintext did not round-trip", size)
+		}
+	}
+}
+
+func TestRecoverReaderTruncated(t *testing.T) {
+	answers, ciphertext := splitStream(t, make([]byte, 2*streamChunkSize+5))
+
+	// drop the last chunk, leaving only full chunks
+	ciphertext = ciphertext[:len(ciphertext)-(5+16)]
+
+	_, err := RecoverReader(answers, bytes.NewReader(ciphertext), io.Discard)
+	if err != ErrCorruptStream {
+		t.Fatalf("Expected %v but was %v", ErrCorruptStream, err)
+	}
+}
+
+func TestRecoverReaderModified(t *testing.T) {
+	answers, ciphertext := splitStream(t, make([]byte, 100))
+	ciphertext[10] ^= 1
+
+	_, err := RecoverReader(answers, bytes.NewReader(ciphertext), io.Discard)
+	if err != ErrCorruptStream {
+		t.Fatalf("Expected %v but was %v", ErrCorruptStream, err)
+	}
+}
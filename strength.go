@@ -0,0 +1,215 @@
+package horcrux
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Strength is an estimate of how hard an answer is to guess.
+type Strength struct {
+	Guesses float64 // Guesses is the estimated number of guesses needed.
+	Bits    float64 // Bits is the base-2 logarithm of Guesses.
+	Score   int     // Score is a zxcvbn-style score from 0 (weakest) to 4.
+	Warning string  // Warning explains why a weak answer is weak.
+}
+
+// commonAnswers are common answers to security questions, most common first,
+// e.g. pets' names, colors, and places.
+var commonAnswers = []string{
+	"max", "bella", "charlie", "buddy", "lucy", "daisy", "spot", "rex",
+	"fluffy", "molly", "bailey", "lucky", "sam", "rocky", "jack", "coco",
+	"blue", "red", "green", "black", "purple", "pink", "yellow", "white",
+	"pizza", "chocolate", "smith", "johnson", "williams", "brown", "jones",
+	"garcia", "miller", "davis", "rodriguez", "martinez", "hernandez",
+	"london", "paris", "new york", "chicago", "springfield", "yes", "no",
+	"none", "password",
+}
+
+var commonAnswerRanks = func() map[string]int {
+	m := make(map[string]int, len(commonAnswers))
+	for i, a := range commonAnswers {
+		m[a] = i
+	}
+	return m
+}()
+
+// EstimateAnswerStrength estimates how many guesses it would take to guess the
+// answer to the question, using heuristics like zxcvbn's. The answer is case
+// folded and split into words, each of which is scored as the cheapest of: a
+// word from the question, a common answer, a recent year, a repeated
+// character, a sequence of characters, or 10 guesses per character, as zxcvbn
+// scores characters it can't match. The estimate ignores the KDF entirely.
+func EstimateAnswerStrength(question, answer string) Strength {
+	a := FoldNormalizer(answer)
+
+	inQuestion := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(FoldNormalizer(question), isWordSeparator) {
+		inQuestion[w] = true
+	}
+
+	var s Strength
+	guesses := 1.0
+	if rank, ok := commonAnswerRanks[a]; ok {
+		guesses = float64(rank + 1)
+		s.Warning = "the answer is a common one"
+	} else {
+		for _, w := range strings.Fields(a) {
+			g, warning := wordGuesses(w, inQuestion)
+			guesses *= g
+			if s.Warning == "" {
+				s.Warning = warning
+			}
+		}
+	}
+
+	// no answer takes more guesses than trying every character
+	guesses = math.Min(guesses, math.Pow(10, float64(utf8.RuneCountInString(a))))
+	s.Guesses = math.Min(math.Max(guesses, 1), math.MaxFloat64)
+	s.Bits = math.Log2(s.Guesses)
+
+	for _, threshold := range []float64{1e3, 1e6, 1e8, 1e10} {
+		if s.Guesses >= threshold {
+			s.Score++
+		}
+	}
+
+	switch {
+	case s.Score >= 3:
+		s.Warning = ""
+	case a == "":
+		s.Warning = "the answer is empty"
+	case s.Warning == "":
+		s.Warning = "the answer is short"
+	}
+	return s
+}
+
+// wordGuesses returns the estimated number of guesses for a single word of an
+// answer and, if it matched a pattern, a warning about it.
+func wordGuesses(w string, inQuestion map[string]bool) (float64, string) {
+	if inQuestion[strings.TrimFunc(w, isWordSeparator)] {
+		return 1, "the answer repeats the question"
+	}
+
+	if rank, ok := commonAnswerRanks[w]; ok {
+		return float64(rank + 1), "the answer is a common one"
+	}
+
+	runes := []rune(w)
+	switch {
+	case isRecentYear(w):
+		return 200, "recent years are easy to guess"
+	case len(runes) > 2 && strings.Count(w, string(runes[0])) == len(runes):
+		return 10 * float64(len(runes)), "repeated characters are easy to guess"
+	case isSequence(runes):
+		return 10 * float64(len(runes)), "sequences like abc or 123 are easy to guess"
+	}
+	return math.Pow(10, float64(len(runes))), ""
+}
+
+// isWordSeparator returns true for the characters between words.
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+// isRecentYear returns true if w is a year from 1900 to 2099.
+func isRecentYear(w string) bool {
+	if len(w) != 4 {
+		return false
+	}
+
+	y, err := strconv.Atoi(w)
+	return err == nil && y >= 1900 && y <= 2099
+}
+
+// isSequence returns true if runes is at least three characters, each one more
+// or one less than the last, like "abc" or "321".
+func isSequence(runes []rune) bool {
+	if len(runes) < 3 {
+		return false
+	}
+
+	d := runes[1] - runes[0]
+	if d != 1 && d != -1 {
+		return false
+	}
+
+	for i := 2; i < len(runes); i++ {
+		if runes[i]-runes[i-1] != d {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrAnswerTooWeak is returned by Split when an answer is too easy to guess for
+// the KDF's cost, as set with WithMinAnswerStrength.
+type ErrAnswerTooWeak struct {
+	Question string  // Question is the question with the weak answer.
+	Bits     float64 // Bits is the strength of the answer plus the KDF's cost.
+	Min      float64 // Min is the minimum strength.
+	Warning  string  // Warning explains why the answer is weak.
+}
+
+func (e ErrAnswerTooWeak) Error() string {
+	return fmt.Sprintf("horcrux: answer to %q is too easy to guess (%.1f bits, minimum is %.1f): %s",
+		e.Question, e.Bits, e.Min, e.Warning)
+}
+
+// kdfCostBits returns the base-2 logarithm of the KDF's cost per guess: N×r×p
+// for scrypt, the time parameter times the memory in KiB for Argon2id, and
+// 2^cost for bcrypt. Custom KDFs' costs are unknown, so count for nothing.
+func (c *splitConfig) kdfCostBits() float64 {
+	if c.customKDF != nil {
+		return 0
+	}
+
+	switch alg, _ := parseKDF(c.kdf); alg {
+	case KDFScrypt:
+		return math.Log2(float64(c.n) * float64(c.r) * float64(c.p))
+	case KDFArgon2id:
+		return math.Log2(float64(c.argon2Time) * float64(c.argon2Memory))
+	case KDFBcrypt:
+		return float64(c.bcryptCost)
+	}
+	return 0
+}
+
+// checkAnswerStrengths returns ErrAnswerTooWeak for the first question, in
+// order, whose normalized answer's strength plus the KDF's cost is less than
+// min bits.
+func checkAnswerStrengths(questions map[string]string, normalize NormalizeFunc, cfg *splitConfig) error {
+	if cfg.minAnswerStrength <= 0 {
+		return nil
+	}
+
+	qs := make([]string, 0, len(questions))
+	for q := range questions {
+		qs = append(qs, q)
+	}
+	sort.Strings(qs)
+
+	cost := cfg.kdfCostBits()
+	for _, q := range qs {
+		a := questions[q]
+		if normalize != nil {
+			a = normalize(a)
+		}
+
+		s := EstimateAnswerStrength(q, a)
+		if bits := s.Bits + cost; bits < cfg.minAnswerStrength {
+			warning := s.Warning
+			if warning == "" {
+				warning = "the KDF is too cheap"
+			}
+			return ErrAnswerTooWeak{Question: q, Bits: bits,
+				Min: cfg.minAnswerStrength, Warning: warning}
+		}
+	}
+	return nil
+}
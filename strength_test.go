@@ -0,0 +1,71 @@
+package horcrux
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateAnswerStrength(t *testing.T) {
+	for _, c := range []struct {
+		question, answer string
+		score            int
+		warning          string
+	}{
+		{"What's your first pet's name?", "Spot", 0, "the answer is a common one"},
+		{"What's your first pet's name?", "New  York", 0, "the answer is a common one"},
+		{"What's your favorite color?", "Favorite", 0, "the answer repeats the question"},
+		{"What's your PIN?", "1234", 0, "sequences like abc or 123 are easy to guess"},
+		{"What's your PIN?", "7777777", 0, "repeated characters are easy to guess"},
+		{"What year did you graduate?", "1987", 0, "recent years are easy to guess"},
+		{"What's your nickname?", "Zed", 1, "the answer is short"},
+		{"What's your nickname?", "", 0, "the answer is empty"},
+		{"What's your real name?", "Rumplestiltskin", 4, ""},
+		{"Where did you meet?", "Under the old oak tree", 4, ""},
+	} {
+		s := EstimateAnswerStrength(c.question, c.answer)
+		if s.Score != c.score || s.Warning != c.warning {
+			t.Fatalf("Expected %q to score %d (%q) but was %d (%q)", c.answer,
+				c.score, c.warning, s.Score, s.Warning)
+		}
+
+		if s.Bits != math.Log2(s.Guesses) {
+			t.Fatalf("Expected %v but was %v", math.Log2(s.Guesses), s.Bits)
+		}
+	}
+}
+
+func TestSplitMinAnswerStrength(t *testing.T) {
+	q := map[string]string{
+		"What's your favorite color?": "red",
+		"What's your real name?":      "Rumplestiltskin",
+	}
+
+	_, err := Split(secret, q, 2, 2<<10, 8, 1, WithMinAnswerStrength(30))
+
+	// red is the 18th most common answer, and the KDF costs 2^14
+	expected := ErrAnswerTooWeak{
+		Question: "What's your favorite color?",
+		Bits:     math.Log2(18) + 14,
+		Min:      30,
+		Warning:  "the answer is a common one",
+	}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+
+	if _, err := Split(secret, q, 2, 2<<10, 8, 1, WithMinAnswerStrength(18)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKDFCostBits(t *testing.T) {
+	cfg := defaultSplitConfig()
+	if bits := cfg.kdfCostBits(); bits != 18 {
+		t.Fatalf("Expected %v but was %v", 18, bits)
+	}
+
+	WithBcrypt(12)(&cfg)
+	if bits := cfg.kdfCostBits(); bits != 12 {
+		t.Fatalf("Expected %v but was %v", 12, bits)
+	}
+}
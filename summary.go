@@ -0,0 +1,23 @@
+package horcrux
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const summaryQuestionLen = 50
+
+// Summarize returns a short, single-line description of the fragment, suitable
+// for selection dialogs and prompts. Questions longer than 50 characters are
+// truncated, so the summary is never longer than 80 characters.
+func (f Fragment) Summarize() string {
+	q := f.Question
+	if f.QuestionIsHashed {
+		q = "(private question)"
+	} else if utf8.RuneCountInString(q) > summaryQuestionLen {
+		r := []rune(q)
+		q = string(r[:summaryQuestionLen]) + "..."
+	}
+
+	return fmt.Sprintf("Fragment #%d: %s (needs %d)", f.ID, q, f.K)
+}
@@ -0,0 +1,45 @@
+package horcrux
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSummarize(t *testing.T) {
+	f := Fragment{ID: 3, K: 2, Question: "What's your first pet's name?"}
+
+	expected := "Fragment #3: What's your first pet's name? (needs 2)"
+	actual := f.Summarize()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestSummarizeLongQuestion(t *testing.T) {
+	q := strings.Repeat("é", 100)
+	f := Fragment{ID: 255, K: 255, Question: q}
+
+	s := f.Summarize()
+	if n := utf8.RuneCountInString(s); n > 80 {
+		t.Fatalf("Expected at most 80 characters but was %d: %s", n, s)
+	}
+
+	if !strings.Contains(s, "#255") {
+		t.Fatalf("Expected the fragment ID in %s", s)
+	}
+
+	if !strings.Contains(s, q[:len("é")*50]+"...") {
+		t.Fatalf("Expected the question prefix in %s", s)
+	}
+}
+
+func TestSummarizePrivateQuestion(t *testing.T) {
+	f := Fragment{ID: 1, K: 2, Question: "abcd", QuestionIsHashed: true}
+
+	expected := "Fragment #1: (private question) (needs 2)"
+	actual := f.Summarize()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
@@ -0,0 +1,114 @@
+// Package testhelpers provides helpers for testing code which uses horcrux.
+//
+// Deriving keys with the recommended scrypt parameters is deliberately slow.
+// These helpers use the cheapest parameters scrypt allows and a seeded source
+// of randomness, so they must never be used outside of tests.
+package testhelpers
+
+import (
+	"log"
+	"testing"
+
+	"github.com/codahale/horcrux"
+)
+
+const (
+	fastN = 2
+	fastR = 1
+	fastP = 1
+
+	seed = 1
+)
+
+// MustSplitFast splits the given secret using the cheapest possible scrypt
+// parameters and a seeded source of randomness for salts and nonces, failing
+// the test if the split fails. It puts horcrux in test mode for the rest of the
+// test (see horcrux.SetTestMode).
+func MustSplitFast(t testing.TB, secret []byte, questions map[string]string, k int) []horcrux.Fragment {
+	t.Helper()
+	horcrux.SetTestMode(t)
+
+	frags, err := horcrux.Split(secret, questions, k, fastN, fastR, fastP,
+		horcrux.WithRandReader(horcrux.NewSeededReader(seed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return frags
+}
+
+// MustRecover recovers the secret from the given answers, failing the test if
+// recovery fails.
+func MustRecover(t testing.TB, answers []horcrux.Answer) []byte {
+	t.Helper()
+
+	s, err := horcrux.Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// Fixture is a fragment set along with the secret and questions it was split
+// from.
+type Fixture struct {
+	Secret    []byte
+	Questions map[string]string
+	K         int
+	Fragments []horcrux.Fragment
+}
+
+// Answers returns correct answers for the first K fragments.
+func (f Fixture) Answers() []horcrux.Answer {
+	answers := make([]horcrux.Answer, f.K)
+	for i := range answers {
+		frag := f.Fragments[i]
+		answers[i] = frag.WithAnswer(f.Questions[frag.Question])
+	}
+	return answers
+}
+
+// fixture is split when the package is initialized, so that no caller pays for
+// the split.
+var fixture, fixtureErr = newFixture()
+
+func newFixture() (Fixture, error) {
+	// there's no test to put horcrux in test mode during initialization, so
+	// silence its warnings about seeded randomness while splitting
+	horcrux.SetLogger(nil)
+	defer horcrux.SetLogger(log.Default())
+
+	f := Fixture{
+		Secret: []byte("my favorite password"),
+		Questions: map[string]string{
+			"What's your first pet's name?":     "Spot",
+			"What's your least favorite food?":  "broccoli",
+			"What's your mother's maiden name?": "Hernandez",
+		},
+		K: 2,
+	}
+
+	var err error
+	f.Fragments, err = horcrux.Split(f.Secret, f.Questions, f.K,
+		fastN, fastR, fastP, horcrux.WithRandReader(horcrux.NewSeededReader(seed)))
+	return f, err
+}
+
+// FixedFragmentSet returns a copy of a 2-of-3 fragment set which is split once,
+// using fast parameters, when the package is initialized.
+func FixedFragmentSet() Fixture {
+	if fixtureErr != nil {
+		panic(fixtureErr)
+	}
+
+	f := fixture
+	f.Secret = append([]byte(nil), fixture.Secret...)
+	f.Questions = make(map[string]string, len(fixture.Questions))
+	for q, a := range fixture.Questions {
+		f.Questions[q] = a
+	}
+	f.Fragments = make([]horcrux.Fragment, len(fixture.Fragments))
+	for i, frag := range fixture.Fragments {
+		f.Fragments[i] = frag.Clone()
+	}
+	return f
+}
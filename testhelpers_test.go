@@ -0,0 +1,65 @@
+package horcrux_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codahale/horcrux"
+	"github.com/codahale/horcrux/testhelpers"
+)
+
+var questions = map[string]string{
+	"What's your first pet's name?":     "Spot",
+	"What's your least favorite food?":  "broccoli",
+	"What's your mother's maiden name?": "Hernandez",
+	"What's your real name?":            "Rumplestiltskin",
+}
+
+func TestFastHelpers(t *testing.T) {
+	secret := []byte("my favorite password")
+
+	start := time.Now()
+	frags, err := horcrux.Split(secret, questions, 2, 2<<14, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow := time.Since(start)
+
+	start = time.Now()
+	fast := testhelpers.MustSplitFast(t, secret, questions, 2)
+	answers := make([]horcrux.Answer, 2)
+	for i := range answers {
+		answers[i] = fast[i].WithAnswer(questions[fast[i].Question])
+	}
+	s := testhelpers.MustRecover(t, answers)
+	quick := time.Since(start)
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+
+	if len(frags) != len(fast) {
+		t.Fatalf("Expected %d fragments but was %d", len(frags), len(fast))
+	}
+
+	if quick >= slow {
+		t.Fatalf("Expected fast helpers (%v) to be faster than Split (%v)",
+			quick, slow)
+	}
+	t.Logf("Split took %v; MustSplitFast and MustRecover took %v", slow, quick)
+}
+
+func TestFixedFragmentSet(t *testing.T) {
+	f := testhelpers.FixedFragmentSet()
+
+	s := testhelpers.MustRecover(t, f.Answers())
+	if string(s) != string(f.Secret) {
+		t.Fatalf("Expected %q but was %q", f.Secret, s)
+	}
+
+	f.Fragments[0].Value[0] ^= 1
+	g := testhelpers.FixedFragmentSet()
+	if f.Fragments[0].Value[0] == g.Fragments[0].Value[0] {
+		t.Fatal("Fixture was shared between callers")
+	}
+}
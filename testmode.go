@@ -0,0 +1,95 @@
+package horcrux
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+var (
+	testMode int32 // the number of tests which have set test mode
+
+	loggerMu sync.RWMutex
+	logger   = log.Default()
+)
+
+// SetTestMode puts the package in test mode until the given test finishes. In
+// test mode, warnings about seeded randomness are suppressed, and fragments
+// are checked for empty nonces and salts as they are encrypted.
+func SetTestMode(tb testing.TB) {
+	atomic.AddInt32(&testMode, 1)
+	tb.Cleanup(func() { atomic.AddInt32(&testMode, -1) })
+}
+
+func inTestMode() bool {
+	return atomic.LoadInt32(&testMode) > 0
+}
+
+// SetLogger sets the logger the package uses for warnings which aren't tied to
+// a single call, such as the use of seeded randomness. By default, warnings
+// are logged to the standard logger. A nil logger disables them.
+func SetLogger(l *log.Logger) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+
+	logger = l
+}
+
+// warnf logs a warning to the package's logger, unless in test mode.
+func warnf(format string, v ...interface{}) {
+	if inTestMode() {
+		return
+	}
+
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+
+	if logger != nil {
+		logger.Printf("horcrux: warning: "+format, v...)
+	}
+}
+
+// seededReader is a deterministic, non-cryptographic source of randomness.
+type seededReader struct {
+	m sync.Mutex
+	r *rand.Rand
+}
+
+// NewSeededReader returns a deterministic source of randomness for use with
+// WithRandReader in tests. It is not cryptographically secure: outside of test
+// mode, a warning is logged when it is created and whenever it is used to
+// split a secret.
+func NewSeededReader(seed int64) io.Reader {
+	warnf("using non-cryptographic randomness (seed %d)", seed)
+	return &seededReader{r: rand.New(rand.NewSource(seed))}
+}
+
+func (s *seededReader) Read(p []byte) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var b [8]byte
+	for i := 0; i < len(p); i += len(b) {
+		binary.LittleEndian.PutUint64(b[:], s.r.Uint64())
+		copy(p[i:], b[:])
+	}
+	return len(p), nil
+}
+
+// checkFragment makes additional checks of newly encrypted fragments in test
+// mode.
+func checkFragment(f *Fragment) error {
+	if !inTestMode() {
+		return nil
+	}
+
+	if len(f.Nonce) == 0 || len(f.Salt) == 0 {
+		return errors.New("horcrux: fragment has an empty nonce or salt")
+	}
+	return nil
+}
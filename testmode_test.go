@@ -0,0 +1,68 @@
+package horcrux
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func captureWarnings(t *testing.T) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	SetLogger(log.New(buf, "", 0))
+	t.Cleanup(func() { SetLogger(log.Default()) })
+	return buf
+}
+
+func TestNewSeededReaderWarns(t *testing.T) {
+	buf := captureWarnings(t)
+
+	_, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithRandReader(NewSeededReader(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "horcrux: warning: using non-cryptographic randomness (seed 1)\n" +
+		"horcrux: warning: splitting a secret with non-cryptographic randomness\n"
+	actual := buf.String()
+	if actual != expected {
+		t.Fatalf("Expected %q but was %q", expected, actual)
+	}
+}
+
+func TestSetTestModeSuppressesWarnings(t *testing.T) {
+	buf := captureWarnings(t)
+	SetTestMode(t)
+
+	_, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithRandReader(NewSeededReader(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("Expected no warnings but was %q", buf.String())
+	}
+}
+
+func TestSeededReaderIsDeterministic(t *testing.T) {
+	SetTestMode(t)
+
+	a, b := make([]byte, 37), make([]byte, 37)
+	_, _ = NewSeededReader(1).Read(a)
+	_, _ = NewSeededReader(1).Read(b)
+
+	if !bytes.Equal(a, b) {
+		t.Fatalf("Expected %x but was %x", a, b)
+	}
+}
+
+func TestCheckFragment(t *testing.T) {
+	SetTestMode(t)
+
+	err := checkFragment(&Fragment{Nonce: []byte{1}})
+	if err == nil || !strings.Contains(err.Error(), "empty nonce or salt") {
+		t.Fatalf("Expected empty salt error but was %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package horcrux
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The text form of a fragment, as returned by String, is its ID and K
+// separated by a slash, then its question, scrypt parameters, salt, value, and
+// answer hint (if any), separated by colons. The text form of an answer is that
+// of its fragment, followed by a colon and the answer. Colons and percent signs
+// in the question, answer hint, and answer are percent-encoded.
+//
+// The text form is for display and debugging: it omits the nonce, KDF, and
+// other fields needed to decrypt the fragment, so fragments should be stored
+// in one of the formats supported by Serialize instead.
+
+var errMalformedText = errors.New("horcrux: malformed fragment string")
+
+// escapeText percent-encodes colons and percent signs in s.
+func escapeText(s string) string {
+	if !strings.ContainsAny(s, ":%") {
+		return s
+	}
+	s = strings.ReplaceAll(s, "%", "%25")
+	return strings.ReplaceAll(s, ":", "%3A")
+}
+
+// unescapeText reverses escapeText.
+func unescapeText(s string) (string, error) {
+	s, err := url.PathUnescape(s)
+	if err != nil {
+		return "", errMalformedText
+	}
+	return s, nil
+}
+
+// ParseFragment parses the text form of a fragment, as returned by String.
+// Only the fields included in the text form are set.
+func ParseFragment(s string) (Fragment, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) != 7 && len(fields) != 8 {
+		return Fragment{}, errMalformedText
+	}
+	return parseFragmentFields(fields)
+}
+
+// ParseAnswer parses the text form of an answer, as returned by String. Only
+// the fields included in the text form are set.
+func ParseAnswer(s string) (Answer, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) != 8 && len(fields) != 9 {
+		return Answer{}, errMalformedText
+	}
+
+	answer, err := unescapeText(fields[len(fields)-1])
+	if err != nil {
+		return Answer{}, err
+	}
+
+	f, err := parseFragmentFields(fields[:len(fields)-1])
+	if err != nil {
+		return Answer{}, err
+	}
+	return Answer{Fragment: f, Answer: answer}, nil
+}
+
+func parseFragmentFields(fields []string) (Fragment, error) {
+	var f Fragment
+
+	id, k, ok := strings.Cut(fields[0], "/")
+	if !ok {
+		return f, errMalformedText
+	}
+
+	ints := []struct {
+		name string
+		s    string
+		v    *int
+	}{
+		{"K", k, &f.K},
+		{"N", fields[2], &f.N},
+		{"R", fields[3], &f.R},
+		{"P", fields[4], &f.P},
+	}
+	for _, i := range ints {
+		n, err := strconv.Atoi(i.s)
+		if err != nil {
+			return f, fmt.Errorf("horcrux: bad %s in fragment string", i.name)
+		}
+		*i.v = n
+	}
+
+	n, err := strconv.ParseUint(id, 10, 8)
+	if err != nil {
+		return f, errors.New("horcrux: bad ID in fragment string")
+	}
+	f.ID = byte(n)
+
+	if f.Question, err = unescapeText(fields[1]); err != nil {
+		return f, err
+	}
+
+	if f.Salt, err = decodeHexField(fields[5]); err != nil {
+		return f, err
+	}
+
+	if f.Value, err = decodeHexField(fields[6]); err != nil {
+		return f, err
+	}
+
+	if len(fields) == 8 {
+		if f.AnswerHint, err = unescapeText(fields[7]); err != nil {
+			return f, err
+		}
+	}
+
+	return f, nil
+}
+
+// decodeHexField decodes a hex-encoded field, returning nil for an empty one as
+// String encodes nil and empty slices the same way.
+func decodeHexField(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errMalformedText
+	}
+	return b, nil
+}
@@ -0,0 +1,82 @@
+package horcrux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFragment(t *testing.T) {
+	f := Fragment{
+		ID:         1,
+		K:          5,
+		N:          2,
+		R:          3,
+		P:          4,
+		Question:   "Time: 100% of the time?",
+		AnswerHint: "Hint: %3A",
+		Salt:       []byte{11},
+		Value:      []byte{12},
+	}
+
+	s := f.String()
+	expected := "1/5:Time%3A 100%25 of the time?:2:3:4:0b:0c:Hint%3A %253A"
+	if s != expected {
+		t.Fatalf("Expected %v but was %v", expected, s)
+	}
+
+	actual, err := ParseFragment(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, f) {
+		t.Fatalf("Expected %v but was %v", f, actual)
+	}
+}
+
+func TestParseAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, hint := range []string{"", "a:hint"} {
+		f := Fragment{
+			ID:         frags[0].ID,
+			K:          frags[0].K,
+			N:          frags[0].N,
+			R:          frags[0].R,
+			P:          frags[0].P,
+			Question:   frags[0].Question,
+			AnswerHint: hint,
+			Salt:       frags[0].Salt,
+			Value:      frags[0].Value,
+		}
+		a := f.WithAnswer("an:answer")
+
+		actual, err := ParseAnswer(a.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, a) {
+			t.Fatalf("Expected %v but was %v", a, actual)
+		}
+	}
+}
+
+func TestParseFragmentMalformed(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"1/5:Q:2:3:4:0b",
+		"1:Q:2:3:4:0b:0c",
+		"1/5:Q:two:3:4:0b:0c",
+		"256/5:Q:2:3:4:0b:0c",
+		"1/5:Q:2:3:4:zz:0c",
+		"1/5:Q%zz:2:3:4:0b:0c",
+	} {
+		if f, err := ParseFragment(s); err == nil {
+			t.Errorf("Expected error for %q but got %v", s, f)
+		}
+	}
+}
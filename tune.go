@@ -0,0 +1,146 @@
+package horcrux
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	maxTuneIterations = 20
+	tuneConfirmations = 3
+)
+
+// ErrCannotTune is returned when no parameters can be found which meet the
+// target duration.
+var ErrCannotTune = errors.New("horcrux: cannot tune KDF parameters")
+
+// argon2IDKey is the function used to calibrate Argon2id, which tests replace.
+var argon2IDKey = argon2.IDKey
+
+// scryptKey is the function used to calibrate scrypt, which tests replace.
+var scryptKey = scrypt.Key
+
+// CalibrateParams finds the smallest scrypt N parameter for which a single
+// derivation with the default R and P parameters takes at least the target
+// duration on this machine, confirmed by a second derivation. N must be a power
+// of 2, so the derivation may take up to twice as long as the target. Returns
+// ErrCannotTune if no such parameter is found within 20 calibration runs.
+func CalibrateParams(targetDuration time.Duration) (ScryptParams, error) {
+	p := defaultScryptParams()
+	password, salt := []byte("password"), make([]byte, saltLen)
+
+	// warm up, so the first measurement isn't slowed by first-use costs
+	if _, err := scryptKey(password, salt, MinScryptN, p.R, p.P, p.KeyLen); err != nil {
+		return p, ErrKDFError{Cause: err}
+	}
+
+	// double N until it's slow enough, twice in a row, since timings are noisy
+	confirmed := false
+	for n, runs := MinScryptN, 0; n <= 1<<30; runs++ {
+		if runs == maxTuneIterations {
+			break
+		}
+
+		start := time.Now()
+		if _, err := scryptKey(password, salt, n, p.R, p.P, p.KeyLen); err != nil {
+			return p, ErrKDFError{Cause: err}
+		}
+
+		if time.Since(start) < targetDuration {
+			confirmed = false
+			n *= 2
+			continue
+		}
+
+		if confirmed {
+			p.N = n
+			return p, nil
+		}
+		confirmed = true
+	}
+	return p, ErrCannotTune
+}
+
+// TuneArgon2id finds the smallest Argon2id time parameter for which a single
+// derivation with the given memory parameter (in KiB) takes at least the
+// target duration on this machine, confirmed by three more derivations. threads
+// is always runtime.NumCPU(). Returns ErrCannotTune if no such parameter is found
+// within 20 calibration runs.
+func TuneArgon2id(targetDuration time.Duration, memory uint32) (timeCost, threads uint32, err error) {
+	threads = uint32(runtime.NumCPU())
+	if threads > 255 {
+		threads = 255
+	}
+
+	password, salt := []byte("password"), make([]byte, saltLen)
+
+	// warm up, so the first measurement isn't slowed by first-use costs
+	_ = argon2IDKey(password, salt, 1, memory, uint8(threads), 32)
+
+	runs := 0
+	measure := func(t uint32) (bool, error) {
+		if runs == maxTuneIterations {
+			return false, ErrCannotTune
+		}
+		runs++
+
+		start := time.Now()
+		_ = argon2IDKey(password, salt, t, memory, uint8(threads), 32)
+		return time.Since(start) >= targetDuration, nil
+	}
+
+	// double the time parameter until it's slow enough
+	hi := uint32(1)
+	for {
+		ok, err := measure(hi)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if ok {
+			break
+		}
+
+		if hi > 1<<30 {
+			return 0, 0, ErrCannotTune
+		}
+		hi *= 2
+	}
+
+	// then find the smallest which is slow enough
+	lo := hi/2 + 1
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		ok, err := measure(mid)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if ok {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	// timings are noisy, so confirm the result with a few more derivations,
+	// going higher until none of them is too fast
+	for confirmed := 0; confirmed < tuneConfirmations; {
+		ok, err := measure(hi)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if ok {
+			confirmed++
+		} else {
+			confirmed = 0
+			hi++
+		}
+	}
+	return hi, threads, nil
+}
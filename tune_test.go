@@ -0,0 +1,93 @@
+package horcrux
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTuneArgon2id(t *testing.T) {
+	// a larger memory parameter takes fewer calibration runs to reach the
+	// target, leaving more of them to confirm it
+	target := 20 * time.Millisecond
+	memory := uint32(8 * 1024)
+
+	timeCost, threads, err := TuneArgon2id(target, memory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if threads == 0 {
+		t.Fatalf("Expected threads but was %v", threads)
+	}
+
+	// timings are noisy, so use the slowest of a few derivations
+	var elapsed time.Duration
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+		_ = argon2IDKey([]byte("password"), make([]byte, saltLen), timeCost,
+			memory, uint8(threads), 32)
+		if d := time.Since(start); d > elapsed {
+			elapsed = d
+		}
+	}
+
+	if elapsed < target {
+		t.Fatalf("Expected at least %v but was %v", target, elapsed)
+	}
+}
+
+func TestTuneArgon2idCannotTune(t *testing.T) {
+	old := argon2IDKey
+	defer func() { argon2IDKey = old }()
+	argon2IDKey = func(password, salt []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+		return make([]byte, keyLen)
+	}
+
+	_, _, err := TuneArgon2id(time.Hour, 1024)
+	if err != ErrCannotTune {
+		t.Fatalf("Expected %v but was %v", ErrCannotTune, err)
+	}
+}
+
+func TestCalibrateParams(t *testing.T) {
+	// real timings are too noisy to test against, so use a fake whose
+	// derivations take 1ms per 1024 iterations
+	old := scryptKey
+	defer func() { scryptKey = old }()
+	scryptKey = func(password, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+		time.Sleep(time.Duration(N/1024) * time.Millisecond)
+		return make([]byte, keyLen), nil
+	}
+
+	p, err := CalibrateParams(20 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// sleeps can overrun, making a lower N look slow enough
+	if p.N < 2<<13 || p.N > 2<<14 {
+		t.Fatalf("Expected between %v and %v but was %v", 2<<13, 2<<14, p.N)
+	}
+
+	if p.R != DefaultScryptParams.R || p.P != DefaultScryptParams.P {
+		t.Fatalf("Expected R=%d, P=%d but was R=%d, P=%d",
+			DefaultScryptParams.R, DefaultScryptParams.P, p.R, p.P)
+	}
+}
+
+func TestCalibrateParamsCannotTune(t *testing.T) {
+	old := scryptKey
+	defer func() { scryptKey = old }()
+	scryptKey = func(password, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+		return make([]byte, keyLen), nil
+	}
+
+	_, err := CalibrateParams(time.Hour)
+	if err != ErrCannotTune {
+		t.Fatalf("Expected %v but was %v", ErrCannotTune, err)
+	}
+}
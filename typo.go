@@ -0,0 +1,96 @@
+package horcrux
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// answerCandidates returns up to max variations of the given answer, most
+// likely first: without trailing punctuation, made singular or plural, and with
+// each pair of adjacent characters swapped. The answer itself is not included.
+func answerCandidates(answer string, max int) []string {
+	seen := map[string]bool{answer: true}
+	var candidates []string
+	add := func(s string) {
+		if len(candidates) < max && s != "" && !seen[s] {
+			seen[s] = true
+			candidates = append(candidates, s)
+		}
+	}
+
+	trimmed := strings.TrimRightFunc(answer, unicode.IsPunct)
+	add(trimmed)
+
+	switch {
+	case strings.HasSuffix(trimmed, "es"):
+		add(trimmed[:len(trimmed)-2])
+		add(trimmed[:len(trimmed)-1])
+	case strings.HasSuffix(trimmed, "s"):
+		add(trimmed[:len(trimmed)-1])
+	default:
+		add(trimmed + "s")
+		add(trimmed + "es")
+	}
+
+	r := []rune(trimmed)
+	for i := 0; i+1 < len(r); i++ {
+		if r[i] == r[i+1] {
+			continue
+		}
+		t := append([]rune{}, r...)
+		t[i], t[i+1] = t[i+1], t[i]
+		add(string(t))
+	}
+
+	return candidates
+}
+
+// openCandidates tries to decrypt the answer's share with each variation of the
+// answer, deriving as many keys at once as WithConcurrency allows. It returns
+// the first share which decrypts, if any.
+func openCandidates(ctx context.Context, a Answer, cfg *recoverConfig) (share, bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	candidates := answerCandidates(a.Answer, cfg.typoBudget)
+	results := make(chan share, len(candidates))
+	sem := make(chan struct{}, workers(cfg.concurrency))
+
+	var wg sync.WaitGroup
+	for _, c := range candidates {
+		wg.Add(1)
+		go func(c string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			b := a
+			b.Answer = c
+			if s, err := openAnswer(ctx, b, cfg); err == nil {
+				results <- s
+				cancel()
+			}
+		}(c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	s, ok := <-results
+	cancel()
+
+	// zero any other shares which were decrypted before the rest were stopped
+	for r := range results {
+		zero(r.value)
+	}
+	return s, ok
+}
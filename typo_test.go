@@ -0,0 +1,65 @@
+package horcrux
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnswerCandidates(t *testing.T) {
+	expected := []string{"Spot", "Spots", "Spotes", "pSot", "Sopt", "Spto"}
+	actual := answerCandidates("Spot!", 10)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestAnswerCandidatesBudget(t *testing.T) {
+	expected := []string{"odg", "dogs"}
+	actual := answerCandidates("odgs", 2)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestRecoverTypoTolerance(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		a := []rune(questions[frags[i].Question])
+		a[1], a[2] = a[2], a[1]
+		answers[i] = frags[i].WithAnswer(string(a) + ".")
+	}
+
+	if _, err := Recover(answers); err == nil {
+		t.Fatal("Expected misspelled answers to fail without typo tolerance")
+	}
+
+	s, err := Recover(answers, WithTypoTolerance(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(s) != string(secret) {
+		t.Fatalf("Expected %q but was %q", secret, s)
+	}
+}
+
+func TestRecoverTypoToleranceExhausted(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := make([]Answer, 2)
+	for i := range answers {
+		answers[i] = frags[i].WithAnswer("wrong")
+	}
+
+	if _, err := Recover(answers, WithTypoTolerance(3)); err == nil {
+		t.Fatal("Expected wrong answers to fail")
+	}
+}
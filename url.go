@@ -0,0 +1,77 @@
+package horcrux
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// URLScheme is the scheme of the URL encoding of a fragment.
+const URLScheme = "horcrux"
+
+// URLString returns a URL-safe encoding of the fragment, suitable for links and
+// QR codes. The question and hint are percent-encoded query parameters, and the
+// remainder of the fragment is base64url-encoded in its binary encoding, e.g.:
+//
+//	horcrux://1?d=AQEB...&q=What%27s+your+real+name%3F
+//
+// Like ComputeHMAC, it returns an empty result if the fragment has no binary
+// encoding, e.g. because its version is unknown.
+func (f Fragment) URLString() string {
+	v := f
+	v.Question = ""
+	v.AnswerHint = ""
+
+	b, err := v.MarshalBinary()
+	if err != nil {
+		return ""
+	}
+
+	q := url.Values{}
+	q.Set("q", f.Question)
+	if f.AnswerHint != "" {
+		q.Set("h", f.AnswerHint)
+	}
+	q.Set("d", base64.RawURLEncoding.EncodeToString(b))
+
+	u := url.URL{
+		Scheme:   URLScheme,
+		Host:     strconv.Itoa(int(f.ID)),
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// ParseURLFragment decodes a fragment from the encoding produced by URLString.
+func ParseURLFragment(s string) (Fragment, error) {
+	var f Fragment
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return f, err
+	}
+
+	if u.Scheme != URLScheme {
+		return f, fmt.Errorf("horcrux: bad fragment URL scheme %q", u.Scheme)
+	}
+
+	q := u.Query()
+	b, err := base64.RawURLEncoding.DecodeString(q.Get("d"))
+	if err != nil {
+		return f, err
+	}
+
+	if err := f.UnmarshalBinary(b); err != nil {
+		return f, err
+	}
+
+	if u.Host != strconv.Itoa(int(f.ID)) {
+		return Fragment{}, fmt.Errorf("horcrux: fragment URL has ID %q but data has ID %d",
+			u.Host, f.ID)
+	}
+
+	f.Question = q.Get("q")
+	f.AnswerHint = q.Get("h")
+	return f, nil
+}
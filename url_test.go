@@ -0,0 +1,83 @@
+package horcrux
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFragmentURLString(t *testing.T) {
+	frags, err := Split(secret, map[string]string{
+		"Where: was your 1st home/flat?": "Leeds",
+		"What's your real name?":         "Rumplestiltskin",
+	}, 2, 2<<10, 8, 1, WithAnswerHint("What's your real name?", "not Bob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		s := f.URLString()
+
+		u, err := url.Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if u.Scheme != URLScheme {
+			t.Fatalf("Expected %v but was %v", URLScheme, u.Scheme)
+		}
+
+		actual, err := ParseURLFragment(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, f) {
+			t.Fatalf("Expected %v but was %v", f, actual)
+		}
+	}
+}
+
+func TestParseURLFragmentBadScheme(t *testing.T) {
+	_, err := ParseURLFragment("https://1?d=AQ")
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	expected := `horcrux: bad fragment URL scheme "https"`
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
+
+func TestParseURLFragmentMismatchedID(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(frags[0].URLString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Host = "9"
+
+	if _, err := ParseURLFragment(u.String()); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestFragmentURLStringUnknownVersion(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.Version = 99
+
+	if s := f.URLString(); s != "" {
+		t.Fatalf("Expected an empty string but was %v", s)
+	}
+}
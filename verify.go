@@ -0,0 +1,25 @@
+package horcrux
+
+// VerifyAnswer checks a single answer by deriving its key and decrypting its
+// fragment's share, without combining any shares, so that a UI can tell the
+// user whether each answer is correct as it is entered. It returns nil if the
+// answer is correct, an ErrWrongAnswer if it is wrong, or another error if the
+// fragment can't be checked, e.g. because it is corrupt or has too many failed
+// attempts. Options such as WithTimeout and WithKDFCache apply as they do to
+// Recover; a cached key makes recovering with the same answer faster later.
+func VerifyAnswer(a Answer, opts ...RecoverOption) error {
+	var cfg recoverConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	s, err := openShare(ctx, a, &cfg)
+	if err != nil {
+		return err
+	}
+	zero(s.value)
+	return nil
+}
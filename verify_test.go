@@ -0,0 +1,46 @@
+package horcrux
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyAnswer(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	if err := VerifyAnswer(f.WithAnswer(questions[f.Question])); err != nil {
+		t.Fatal(err)
+	}
+
+	err = VerifyAnswer(f.WithAnswer("wrong"))
+
+	var wrong ErrWrongAnswer
+	if !errors.As(err, &wrong) {
+		t.Fatalf("Expected ErrWrongAnswer but was %v", err)
+	}
+
+	if wrong.ID != int(f.ID) {
+		t.Fatalf("Expected %v but was %v", f.ID, wrong.ID)
+	}
+}
+
+func TestVerifyAnswerTooManyAttempts(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := frags[0]
+	f.MaxAttempts = 1
+	f = IncrementAttempt(f)
+
+	err = VerifyAnswer(f.WithAnswer(questions[f.Question]))
+	expected := ErrTooManyAttempts{ID: int(f.ID)}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+}
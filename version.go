@@ -0,0 +1,87 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// FragmentVersion1 is the original fragment format, which always uses
+	// scrypt and has no KDF field.
+	FragmentVersion1 = 1
+
+	// FragmentVersion2 is the fragment format with a KDF field. Split always
+	// produces version 2 fragments, but Recover accepts either version, and
+	// can combine fragments of both.
+	FragmentVersion2 = 2
+)
+
+// version returns the fragment's format version. Fragments without a version
+// predate versioning and are version 1.
+func (f Fragment) version() uint8 {
+	if f.Version == 0 {
+		return FragmentVersion1
+	}
+	return f.Version
+}
+
+// UpgradeFragment decrypts the share in a version 1 fragment with the given
+// answer and re-encrypts it as a version 2 fragment, using the given options.
+// The fragment's answer hint, normalizer, locale, answer separator, and
+// metadata are kept unless overridden.
+func UpgradeFragment(f Fragment, answer string, opts ...SplitOption) (Fragment, error) {
+	if v := f.version(); v != FragmentVersion1 {
+		return f, fmt.Errorf("horcrux: fragment %d is already version %d",
+			f.ID, v)
+	}
+
+	if f.QuestionIsHashed {
+		return f, errors.New("horcrux: cannot upgrade a fragment with a private question")
+	}
+
+	cfg := defaultSplitConfig()
+	cfg.normalizerID = f.NormalizerID
+	cfg.locale = f.Locale
+	cfg.setID = f.SetID
+	cfg.answerSeparator = f.AnswerSeparator
+	if f.AnswerHint != "" {
+		cfg.hints = map[string]string{f.Question: f.AnswerHint}
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return f, err
+	}
+
+	normalize, err := cfg.normalizer()
+	if err != nil {
+		return f, err
+	}
+
+	ctx, cancel := withTimeout(cfg.ctx, cfg.timeout)
+	defer cancel()
+
+	var rcfg recoverConfig
+	s, err := openShare(ctx, f.WithAnswer(answer), &rcfg)
+	if err != nil {
+		return f, err
+	}
+	defer zero(s.value)
+
+	normalized := answer
+	if normalize != nil {
+		normalized = normalize(answer)
+	}
+
+	var u Fragment
+	err = encryptShare(ctx, &u, f.ID, f.K, f.Question, normalized, answer,
+		s.value, &cfg)
+	if err != nil {
+		return f, err
+	}
+
+	u.Metadata = f.Clone().Metadata
+	return u, nil
+}
@@ -0,0 +1,173 @@
+package horcrux
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// splitV1 returns the fragments of a split as they would have been written
+// before fragments were versioned.
+func splitV1(t *testing.T) []Fragment {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, f := range frags {
+		frags[i] = unbind(t, f, questions[f.Question])
+		frags[i].Version = 0
+		frags[i].KDF = ""
+		frags[i].SetID = nil
+		frags[i].StructureChecksum = nil
+	}
+	return frags
+}
+
+func TestSplitWritesVersion2(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		if f.Version != FragmentVersion2 {
+			t.Fatalf("Expected %v but was %v", FragmentVersion2, f.Version)
+		}
+	}
+}
+
+func TestFragmentBinaryVersion1(t *testing.T) {
+	for _, f := range splitV1(t) {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if b[0] != FragmentVersion1 {
+			t.Fatalf("Expected %v but was %v", FragmentVersion1, b[0])
+		}
+
+		var actual Fragment
+		if err := actual.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		f.Version = FragmentVersion1
+		if !reflect.DeepEqual(actual, f) {
+			t.Fatalf("Expected %v but was %v", f, actual)
+		}
+	}
+}
+
+func TestFragmentBinaryVersion1Bcrypt(t *testing.T) {
+	f := Fragment{Version: FragmentVersion1, KDF: KDFBcrypt, BcryptCost: 10}
+	if _, err := f.MarshalBinary(); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestFragmentBinaryVersion2(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 0, 0, 0, WithBcrypt(MinBcryptCost))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range frags {
+		b, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual Fragment
+		if err := actual.UnmarshalBinary(b); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, f) {
+			t.Fatalf("Expected %v but was %v", f, actual)
+		}
+	}
+}
+
+func TestRecoverMixedVersions(t *testing.T) {
+	frags := splitV1(t)
+
+	// only some fragments of an old backup may have been upgraded
+	u, err := UpgradeFragment(frags[1], questions[frags[1].Question],
+		WithScryptParams(2<<10, 8, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := Recover([]Answer{
+		frags[0].WithAnswer(questions[frags[0].Question]),
+		u.WithAnswer(questions[u.Question]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestUpgradeFragment(t *testing.T) {
+	frags := splitV1(t)
+
+	answers := make([]Answer, 2)
+	for i, f := range frags[:2] {
+		u, err := UpgradeFragment(f, questions[f.Question],
+			WithScryptParams(2<<10, 8, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if u.Version != FragmentVersion2 || u.KDF != KDFScrypt {
+			t.Fatalf("Expected a version 2 scrypt fragment but was %v/%v",
+				u.Version, u.KDF)
+		}
+
+		if u.ID != f.ID || u.K != f.K || u.Question != f.Question {
+			t.Fatalf("Expected %v but was %v", f, u)
+		}
+
+		answers[i] = u.WithAnswer(questions[u.Question])
+	}
+
+	actual, err := Recover(answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+}
+
+func TestUpgradeFragmentWrongAnswer(t *testing.T) {
+	f := splitV1(t)[0]
+
+	if _, err := UpgradeFragment(f, "wrong"); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
+
+func TestUpgradeFragmentAlreadyUpgraded(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = UpgradeFragment(frags[0], questions[frags[0].Question])
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	expected := "horcrux: fragment 1 is already version 2"
+	actual := err.Error()
+	if actual != expected {
+		t.Fatalf("Expected %v but was %v", expected, actual)
+	}
+}
@@ -0,0 +1,121 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+)
+
+// weight returns the number of shares the fragment holds. Fragments without a
+// weight hold one share.
+func (f Fragment) weight() int {
+	if f.Weight < 1 {
+		return 1
+	}
+	return f.Weight
+}
+
+// weight returns the number of shares given to the question.
+func (c *splitConfig) weight(q string) int {
+	if w, ok := c.weights[q]; ok {
+		return w
+	}
+	return 1
+}
+
+// totalWeight returns the total number of shares given to the questions.
+func (c *splitConfig) totalWeight(questions map[string]string) int {
+	n := 0
+	for q := range questions {
+		n += c.weight(q)
+	}
+	return n
+}
+
+// answersWeight returns the number of distinct shares held by the answers'
+// fragments, so that the same fragment given twice only counts once.
+func answersWeight(answers []Answer) int {
+	held := make(map[int]bool)
+	for _, a := range answers {
+		for i := 0; i < a.weight(); i++ {
+			held[int(a.ID)+i] = true
+		}
+	}
+	return len(held)
+}
+
+// sharesWeight returns the number of distinct shares in the given decrypted
+// shares, counting each share of a weighted fragment, and each share ID only
+// once.
+func sharesWeight(shares []share) int {
+	held := make(map[int]bool)
+	for _, s := range shares {
+		for i := 0; i < s.weight(); i++ {
+			held[int(s.id)+i] = true
+		}
+	}
+	return len(held)
+}
+
+// weight returns the number of shares the decrypted share holds.
+func (s share) weight() int {
+	if s.n < 1 {
+		return 1
+	}
+	return s.n
+}
+
+// bundleShares concatenates the n shares starting at the given ID, which a
+// fragment with a weight of n holds.
+func bundleShares(shares map[byte][]byte, id byte, n int) ([]byte, error) {
+	var b []byte
+	for i := 0; i < n; i++ {
+		v, ok := shares[id+byte(i)]
+		if !ok || int(id)+i > 255 {
+			return nil, errors.New("horcrux: weighted shares must have consecutive IDs")
+		}
+		b = append(b, v...)
+	}
+	return b, nil
+}
+
+// expandShares returns the given shares with each share of a weighted fragment
+// split out into the individual shares it holds. The individual shares share
+// memory with the originals.
+func expandShares(shares []share) []share {
+	expanded := make([]share, 0, sharesWeight(shares))
+	for _, s := range shares {
+		n := s.weight()
+		size := len(s.value) / n
+		for i := 0; i < n; i++ {
+			expanded = append(expanded, share{
+				id:    s.id + byte(i),
+				value: s.value[i*size : (i+1)*size],
+			})
+		}
+	}
+	return expanded
+}
+
+// unbundleShare checks that the value decrypted from the fragment holds its
+// weight in shares, and that each matches its commitment, if any.
+func unbundleShare(f Fragment, v []byte, commitments [][]byte) (share, error) {
+	s := share{id: f.ID, value: v, n: f.weight()}
+	if len(v)%s.n != 0 || int(f.ID)+s.n-1 > 255 {
+		return share{}, ErrCorruptFragment{
+			ID:     int(f.ID),
+			Reason: fmt.Sprintf("does not hold %d shares", s.n),
+		}
+	}
+
+	if commitments != nil {
+		for _, p := range expandShares([]share{s}) {
+			if !VerifyShareCommitment(int(p.id), p.value, commitments) {
+				return share{}, ErrCorruptFragment{
+					ID:     int(f.ID),
+					Reason: "does not match its commitment",
+				}
+			}
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,148 @@
+package horcrux
+
+import (
+	"bytes"
+	"testing"
+)
+
+const weightedQuestion = "What's your real name?"
+
+func splitWeighted(t *testing.T) (weighted Answer, others []Answer) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1,
+		WithWeights(map[string]int{weightedQuestion: 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range allAnswers(frags) {
+		if a.Question == weightedQuestion {
+			weighted = a
+		} else {
+			others = append(others, a)
+		}
+	}
+	return weighted, others
+}
+
+func TestSplitWithWeights(t *testing.T) {
+	weighted, others := splitWeighted(t)
+
+	if weighted.Weight != 2 {
+		t.Fatalf("Expected %v but was %v", 2, weighted.Weight)
+	}
+
+	// the weighted fragment's shares don't overlap with any other's
+	used := make(map[byte]bool)
+	for _, a := range append(others, weighted) {
+		for i := 0; i < a.weight(); i++ {
+			id := a.ID + byte(i)
+			if used[id] {
+				t.Fatalf("Share %d is held by more than one fragment", id)
+			}
+			used[id] = true
+		}
+	}
+
+	s, err := Recover([]Answer{weighted, others[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+}
+
+func TestRecoverWithoutWeightedAnswer(t *testing.T) {
+	_, others := splitWeighted(t)
+
+	_, err := Recover(others[:2])
+
+	expected := ErrTooFewAnswers{Need: 3, Have: 2}
+	if err != expected {
+		t.Fatalf("Expected %v but was %v", expected, err)
+	}
+
+	s, err := Recover(others)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+}
+
+func TestRecoverEarlyWeighted(t *testing.T) {
+	weighted, others := splitWeighted(t)
+
+	s, err := RecoverEarly([]Answer{weighted, others[0], others[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+}
+
+func TestRecoverPartialWeighted(t *testing.T) {
+	weighted, _ := splitWeighted(t)
+
+	res, err := RecoverPartial([]Answer{weighted})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.RemainingNeeded != 1 {
+		t.Fatalf("Expected %v but was %v", 1, res.RemainingNeeded)
+	}
+}
+
+func TestDecryptSharesWeighted(t *testing.T) {
+	weighted, _ := splitWeighted(t)
+
+	shares, err := DecryptShares([]Answer{weighted})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if s := shares[int(weighted.ID)+i]; len(s) != len(secret) {
+			t.Fatalf("Expected a %d-byte share %d but was %v", len(secret),
+				int(weighted.ID)+i, s)
+		}
+	}
+}
+
+func TestRefreshFragmentsWeighted(t *testing.T) {
+	weighted, others := splitWeighted(t)
+
+	refreshed, err := RefreshFragments(append(others, weighted))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers := allAnswers(refreshed)
+	last := answers[len(answers)-1]
+	if last.Weight != 2 {
+		t.Fatalf("Expected %v but was %v", 2, last.Weight)
+	}
+
+	s, err := Recover([]Answer{last, answers[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(s, secret) {
+		t.Fatalf("Expected %v but was %v", secret, s)
+	}
+}
+
+func TestWithWeightsInvalid(t *testing.T) {
+	_, err := Split(secret, questions, 2, 2<<10, 8, 1,
+		WithWeights(map[string]int{weightedQuestion: 0}))
+	if err == nil {
+		t.Fatal("Expected error but got none")
+	}
+}
@@ -0,0 +1,76 @@
+package horcrux
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// RecoverInteractive walks the user through recovering the secret from the
+// given fragments. It asks each fragment's question in turn on stderr, reading
+// the answers from in like ReadAnswers, and checks each answer as soon as it is
+// given. A wrong answer can be tried again, or skipped by leaving it empty.
+// After each correct answer, it reports how many more are needed. Returns the
+// secret once enough answers are correct, or an error if the fragments run out
+// first.
+func RecoverInteractive(frags []Fragment, in *os.File, opts ...RecoverOption) ([]byte, error) {
+	return recoverInteractive(frags, os.Stderr, answerReader(in), opts)
+}
+
+func recoverInteractive(frags []Fragment, w io.Writer, read func() (string, error), opts []RecoverOption) ([]byte, error) {
+	if len(frags) == 0 {
+		return nil, errors.New("horcrux: no fragments")
+	}
+
+	k := frags[0].K
+	fmt.Fprintf(w, "Answer %d of these %d questions to recover the secret.\n",
+		k, len(frags))
+
+	s := NewRecoverySession(opts...)
+	correct := 0
+	for _, f := range frags {
+		for {
+			prompt(w, f)
+
+			a, err := read()
+			if err != nil {
+				return nil, err
+			}
+
+			a = strings.TrimSpace(a)
+			if a == "" {
+				fmt.Fprintln(w, "Skipped.")
+				break
+			}
+
+			err = s.Add(f.WithAnswer(a))
+
+			var auth ErrAuthenticationFailed
+			if errors.As(err, &auth) {
+				fmt.Fprintln(w, "That answer is not correct. Try again, or leave it empty to skip.")
+				continue
+			}
+
+			if err != nil {
+				fmt.Fprintf(w, "This fragment can't be used: %v\n", err)
+				break
+			}
+
+			// a weighted fragment's answer counts for each of its shares
+			correct += f.weight()
+			if correct >= f.K {
+				fmt.Fprintln(w, "Correct. Recovering the secret.")
+				return s.Complete()
+			}
+
+			fmt.Fprintf(w, "Correct. %d more correct answers needed.\n",
+				f.K-correct)
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("horcrux: only %d of the %d answers needed were correct",
+		correct, k)
+}
@@ -0,0 +1,107 @@
+package horcrux
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// lines returns a function which reads the given lines in turn.
+func lines(l ...string) func() (string, error) {
+	return func() (string, error) {
+		if len(l) == 0 {
+			return "", io.EOF
+		}
+		s := l[0]
+		l = l[1:]
+		return s, nil
+	}
+}
+
+func TestRecoverInteractive(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := lines(
+		"wrong",
+		questions[frags[0].Question],
+		"",
+		questions[frags[2].Question],
+	)
+
+	var w bytes.Buffer
+	actual, err := recoverInteractive(frags, &w, read, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+
+	for _, s := range []string{
+		"Answer 2 of these 4 questions",
+		"That answer is not correct",
+		"1 more correct answers needed",
+		"Skipped.",
+		"Recovering the secret",
+	} {
+		if !strings.Contains(w.String(), s) {
+			t.Errorf("Expected output to contain %q but was %q", s, w.String())
+		}
+	}
+}
+
+func TestRecoverInteractiveWeighted(t *testing.T) {
+	frags, err := SplitWithOptions(secret, questions, 3,
+		WithScryptParams(2<<10, 8, 1),
+		WithWeights(map[string]int{"What's your real name?": 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ask the weighted question first, so that one more answer is enough
+	for i, f := range frags {
+		if f.Weight == 2 {
+			frags[0], frags[i] = frags[i], frags[0]
+		}
+	}
+	read := []string{
+		questions[frags[0].Question],
+		questions[frags[1].Question],
+	}
+
+	var w bytes.Buffer
+	actual, err := recoverInteractive(frags, &w, lines(read...), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(actual, secret) {
+		t.Fatalf("Expected %v but was %v", secret, actual)
+	}
+
+	if strings.Contains(w.String(), "Skipped.") {
+		t.Fatalf("Expected no more questions once enough shares were correct but was %q",
+			w.String())
+	}
+}
+
+func TestRecoverInteractiveNotEnough(t *testing.T) {
+	frags, err := Split(secret, questions, 3, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	read := lines(questions[frags[0].Question], "", "", "")
+
+	actual, err := recoverInteractive(frags, io.Discard, read, nil)
+
+	expected := "horcrux: only 1 of the 3 answers needed were correct"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected %v but was %v (%v)", expected, err, actual)
+	}
+}
@@ -0,0 +1,48 @@
+package horcrux
+
+import "runtime"
+
+// A Destroyer holds secret material, such as decrypted shares or derived keys,
+// which Destroy zeroes and discards. Long-lived processes should call Destroy
+// as soon as the secret material is no longer needed, rather than leaving it
+// on the heap until it is garbage collected.
+type Destroyer interface {
+	Destroy()
+}
+
+// Zeroize overwrites all of the fragment's byte slices, including those of its
+// alternates, with zeros.
+func (f *Fragment) Zeroize() {
+	zero(f.SetID)
+	zero(f.KDFParams)
+	zero(f.Nonce)
+	zero(f.Salt)
+	zero(f.Value)
+	zero(f.EscrowedAnswer)
+	zero(f.StructureChecksum)
+	zero(f.HMAC)
+	zero(f.RevocationSignature)
+	for _, alt := range f.Alternates {
+		zero(alt.Salt)
+		zero(alt.Nonce)
+		zero(alt.Value)
+	}
+}
+
+// Destroy zeroizes the fragment, making fragments Destroyers like the other
+// holders of secret material.
+func (f *Fragment) Destroy() {
+	f.Zeroize()
+}
+
+// NewSensitiveFragment returns a copy of the given fragment which will be
+// zeroized when it is garbage collected. This is a best-effort measure only:
+// the Go runtime does not guarantee that finalizers will ever run, and copies
+// of the fragment's fields made elsewhere are not affected. Call Zeroize
+// explicitly when the fragment is no longer needed.
+func NewSensitiveFragment(f Fragment) *Fragment {
+	s := f.Clone()
+	p := &s
+	runtime.SetFinalizer(p, (*Fragment).Zeroize)
+	return p
+}
@@ -0,0 +1,164 @@
+package horcrux
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestZeroize(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frag := frags[0]
+	frag.HMAC = frag.ComputeHMAC([]byte("key"))
+	frag.EscrowedAnswer = []byte("escrowed")
+	frag.RevocationSignature = []byte("signature")
+
+	frag.Zeroize()
+
+	for _, b := range [][]byte{
+		frag.Nonce, frag.Salt, frag.Value, frag.EscrowedAnswer,
+		frag.RevocationSignature, frag.HMAC,
+	} {
+		if !bytes.Equal(b, make([]byte, len(b))) {
+			t.Fatalf("Expected %x to be zeroed", b)
+		}
+	}
+}
+
+func TestZeroizeEveryByteSlice(t *testing.T) {
+	var frag Fragment
+	fillByteSlices(reflect.ValueOf(&frag).Elem())
+
+	frag.Zeroize()
+
+	checkByteSlicesZeroed(t, "Fragment", reflect.ValueOf(frag))
+}
+
+func TestFragmentDestroy(t *testing.T) {
+	frags, err := SplitWithOptions(secret, map[string]string{
+		"What's your favorite color?": "blue",
+		"What's your pet's name?":     "Spot",
+	}, 2, WithScryptParams(2<<10, 8, 1),
+		WithAlternateAnswers(map[string][]string{
+			"What's your pet's name?": {"Rover"},
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alternates := 0
+	for _, f := range frags {
+		alternates += len(f.Alternates)
+	}
+
+	if alternates != 1 {
+		t.Fatalf("Expected %v but was %v", 1, alternates)
+	}
+
+	for i := range frags {
+		var d Destroyer = &frags[i]
+		d.Destroy()
+
+		checkByteSlicesZeroed(t, "Fragment", reflect.ValueOf(frags[i]))
+	}
+}
+
+// fillByteSlices sets every byte slice in v, including those in nested structs
+// and slices of structs, to non-zero bytes.
+func fillByteSlices(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fillByteSlices(v.Field(i))
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte{1, 2, 3})
+			return
+		}
+
+		if v.Type().Elem().Kind() == reflect.Struct {
+			v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+			for i := 0; i < v.Len(); i++ {
+				fillByteSlices(v.Index(i))
+			}
+		}
+	}
+}
+
+// checkByteSlicesZeroed fails the test if any byte slice in v, including those
+// in nested structs and slices of structs, holds a non-zero byte.
+func checkByteSlicesZeroed(t *testing.T, path string, v reflect.Value) {
+	t.Helper()
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			checkByteSlicesZeroed(t, path+"."+v.Type().Field(i).Name, v.Field(i))
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if b := v.Bytes(); !bytes.Equal(b, make([]byte, len(b))) {
+				t.Fatalf("Expected %s to be zeroed but was %x", path, b)
+			}
+			return
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			checkByteSlicesZeroed(t, path, v.Index(i))
+		}
+	}
+}
+
+func TestNewSensitiveFragment(t *testing.T) {
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSensitiveFragment(frags[0])
+	s.Zeroize()
+
+	if bytes.Equal(frags[0].Value, s.Value) {
+		t.Fatal("Expected the original fragment to be unaffected")
+	}
+}
+
+func TestSplitAndRecoverZeroKeys(t *testing.T) {
+	var mu sync.Mutex
+	var keys [][]byte
+	old := derive
+	defer func() { derive = old }()
+	derive = func(f Fragment, answer string) ([]byte, error) {
+		k, err := old(f, answer)
+
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, k)
+		return k, err
+	}
+
+	frags, err := Split(secret, questions, 2, 2<<10, 8, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Recover(allAnswers(frags)[:2]); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != len(questions)+2 {
+		t.Fatalf("Expected %d keys but was %d", len(questions)+2, len(keys))
+	}
+
+	for _, k := range keys {
+		if !bytes.Equal(k, make([]byte, len(k))) {
+			t.Fatalf("Expected %x to be zeroed", k)
+		}
+	}
+}